@@ -8,6 +8,8 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/wailsapp/wails/v2/pkg/runtime"
@@ -17,9 +19,22 @@ import (
 type App struct {
 	ctx context.Context
 	db  *services.DatabaseService
+	// dbErr is set when the database could not be opened or migrated;
+	// the app then runs without a database and reports it to the UI.
+	dbErr error
+	// backups takes startup, scheduled and pre-update database snapshots
+	backups *services.BackupService
+	// user is the logged-in account; nil until Login succeeds
+	user   *models.User
+	userMu sync.RWMutex
+	// settings holds the user-adjustable options from the data directory
+	settings *services.SettingsService
 	// updater handles version checks and downloads
-	updater        *services.UpdaterService
+	updater *services.UpdaterService
+	// updateWake restarts the update loop's wait after the schedule changes
+	updateWake     chan struct{}
 	exePath        string
+	dataDir        string
 	currentVersion string
 	latestTag      string
 	latestAssetURL string
@@ -28,7 +43,9 @@ type App struct {
 
 // NewApp creates a new App application struct
 func NewApp() *App {
-	return &App{}
+	a := &App{updateWake: make(chan struct{}, 1)}
+	initDataDir(a)
+	return a
 }
 
 // startup is called when the app starts. The context is saved
@@ -37,123 +54,1448 @@ func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
 	// Initialize SQLite database in user config directory
 	initDatabase(a)
-	// Resolve executable path for updater and DB colocated files
-	exePath, err := os.Executable()
-	if err != nil || exePath == "" {
-		exePath, _ = os.Getwd()
+	initBackups(a)
+	cfg := a.currentSettings()
+	a.updater = services.NewUpdaterService(a.exePath, cfg.UpdateRepoOwner, cfg.UpdateRepoName)
+	if a.settings != nil {
+		a.settings.OnChange(a.applySettings)
+	}
+	// Start background update watcher (check-only; no auto-download/apply)
+	go a.backgroundUpdateLoop()
+	go a.reservationExpiryLoop()
+}
+
+// domReady is called once the frontend has loaded and can receive events.
+func (a *App) domReady(ctx context.Context) {
+	if a.dbErr != nil {
+		runtime.EventsEmit(ctx, "db:error", a.dbErr.Error())
+		return
+	}
+	lots, err := a.db.ListExpiring(a.currentSettings().ExpiryWarningDays)
+	if err != nil {
+		log.Printf("expiring lots: %v", err)
+	} else if len(lots) > 0 {
+		runtime.EventsEmit(ctx, "lots:expiring", lots)
+	}
+}
+
+// DatabaseError returns the database startup error, or an empty string if the database is usable.
+func (a *App) DatabaseError() string {
+	if a.dbErr == nil {
+		return ""
+	}
+	return a.dbErr.Error()
+}
+
+// authorize checks that a user is logged in and their role allows actions
+// that require role.
+func (a *App) authorize(role string) error {
+	user := a.currentUser()
+	if user == nil {
+		return services.ErrNotAuthenticated
+	}
+	if !models.RoleAllows(user.Role, role) {
+		return fmt.Errorf("%w: requires %s role", services.ErrForbidden, role)
+	}
+	return nil
+}
+
+func (a *App) currentUser() *models.User {
+	a.userMu.RLock()
+	defer a.userMu.RUnlock()
+	return a.user
+}
+
+// setUser changes the session user and emits "auth:changed" with it (nil on logout).
+func (a *App) setUser(user *models.User) {
+	a.userMu.Lock()
+	a.user = user
+	a.userMu.Unlock()
+	runtime.EventsEmit(a.ctx, "auth:changed", user)
+}
+
+// NeedsSetup reports whether no account exists yet; the frontend then asks
+// for the first admin instead of showing the login screen.
+func (a *App) NeedsSetup() (bool, error) {
+	if a.db == nil || a.db.DB == nil {
+		return false, fmt.Errorf("database not initialised")
+	}
+	has, err := a.db.HasUsers()
+	return !has, err
+}
+
+// SetupAdmin creates the first admin account and logs it in.
+func (a *App) SetupAdmin(username string, password string) (*models.User, error) {
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	user, err := a.db.SetupAdmin(username, password)
+	if err != nil {
+		return nil, err
+	}
+	a.setUser(user)
+	return user, nil
+}
+
+// Login starts a session for the user with the given credentials.
+func (a *App) Login(username string, password string) (*models.User, error) {
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	user, err := a.db.Authenticate(username, password)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("user %s logged in", user.Username)
+	a.setUser(user)
+	return user, nil
+}
+
+// Logout ends the current session.
+func (a *App) Logout() {
+	a.setUser(nil)
+}
+
+// CurrentUser returns the logged-in user, or nil when nobody is logged in.
+func (a *App) CurrentUser() *models.User {
+	return a.currentUser()
+}
+
+// ListUsers returns all accounts. Admin only.
+func (a *App) ListUsers() ([]models.User, error) {
+	if err := a.authorize(models.RoleAdmin); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.ListUsers()
+}
+
+// CreateUser adds an account. Admin only.
+func (a *App) CreateUser(input models.UserInput) (*models.User, error) {
+	if err := a.authorize(models.RoleAdmin); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.CreateUser(input)
+}
+
+// UpdateUser changes an account; an empty password keeps the current one.
+// Admin only. Changing the logged-in account updates the session, and
+// disabling it logs out.
+func (a *App) UpdateUser(id uint, input models.UserInput) (*models.User, error) {
+	if err := a.authorize(models.RoleAdmin); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	user, err := a.db.UpdateUser(id, input)
+	if err != nil {
+		return nil, err
+	}
+	if current := a.currentUser(); current != nil && current.ID == user.ID {
+		if user.Disabled {
+			a.setUser(nil)
+		} else {
+			a.setUser(user)
+		}
+	}
+	return user, nil
+}
+
+// ChangePassword changes the logged-in user's own password.
+func (a *App) ChangePassword(current string, next string) error {
+	if err := a.authorize(models.RoleViewer); err != nil {
+		return err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return fmt.Errorf("database not initialised")
+	}
+	return a.db.ChangePassword(a.currentUser().ID, current, next)
+}
+
+// Greet returns a greeting for the given name
+func (a *App) Greet(name string) string {
+	return fmt.Sprintf("Hello %s, It's show time!", name)
+}
+
+// CreateItem creates a new inventory item.
+// SKU and barcodes must not be used by another item.
+func (a *App) CreateItem(input models.ItemInput) (*models.Item, error) {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	item, err := a.db.CreateItem(input)
+	if err != nil {
+		return nil, err
+	}
+	runtime.EventsEmit(a.ctx, "items:changed")
+	return item, nil
+}
+
+// UpdateItem updates existing item by id.
+// Catalog fields left nil in input keep their stored values.
+func (a *App) UpdateItem(id uint, input models.ItemInput) (*models.Item, error) {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	item, err := a.db.UpdateItem(id, input)
+	if err != nil {
+		return nil, err
+	}
+	runtime.EventsEmit(a.ctx, "items:changed")
+	return item, nil
+}
+
+// WithdrawQuantity decreases quantity for the item by delta (must be positive)
+// at a location; locationID 0 means the default location.
+// Fractional deltas are allowed up to the item's precision. Reserved stock
+// is only withdrawn against its reservation (reservationID, 0 for none).
+func (a *App) WithdrawQuantity(id uint, locationID uint, delta models.Quantity, comment string, reservationID uint) (*models.Item, error) {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return nil, err
+	}
+	if delta <= 0 {
+		return nil, fmt.Errorf("delta must be positive")
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	item, err := a.db.WithdrawQuantity(id, locationID, delta, comment, reservationID)
+	if err != nil {
+		return nil, err
+	}
+	runtime.EventsEmit(a.ctx, "items:changed")
+	return item, nil
+}
+
+// ReceiveQuantity increases quantity for the item by delta (must be positive)
+// at a location; locationID 0 means the default location.
+// reference is the supplier invoice / delivery note the goods arrived with;
+// unitCost is the purchase price per unit, nil for the current average cost.
+func (a *App) ReceiveQuantity(id uint, locationID uint, delta models.Quantity, comment string, reference string, unitCost *models.Money) (*models.Item, error) {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return nil, err
+	}
+	if delta <= 0 {
+		return nil, fmt.Errorf("delta must be positive")
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	item, err := a.db.ReceiveQuantity(id, locationID, delta, comment, reference, unitCost)
+	if err != nil {
+		return nil, err
+	}
+	runtime.EventsEmit(a.ctx, "items:changed")
+	return item, nil
+}
+
+// DeleteItem moves the item to the recycle bin (soft delete).
+func (a *App) DeleteItem(id uint) error {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return fmt.Errorf("database not initialised")
+	}
+	if err := a.db.DeleteItem(id); err != nil {
+		return err
+	}
+	runtime.EventsEmit(a.ctx, "items:changed")
+	return nil
+}
+
+// RestoreItem brings a soft-deleted item back.
+func (a *App) RestoreItem(id uint) (*models.Item, error) {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	item, err := a.db.RestoreItem(id)
+	if err != nil {
+		return nil, err
+	}
+	runtime.EventsEmit(a.ctx, "items:changed")
+	return item, nil
+}
+
+// ListDeletedItems returns soft-deleted items.
+func (a *App) ListDeletedItems() ([]models.Item, error) {
+	if err := a.authorize(models.RoleViewer); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.ListDeletedItems()
+}
+
+// ListItemMovements returns the stock ledger of one item, newest first.
+func (a *App) ListItemMovements(id uint) ([]models.StockMovement, error) {
+	if err := a.authorize(models.RoleViewer); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.ListItemMovements(id)
+}
+
+// ListMovements returns the latest stock movements across all items.
+// limit <= 0 returns the full ledger.
+func (a *App) ListMovements(limit int) ([]models.StockMovement, error) {
+	if err := a.authorize(models.RoleViewer); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.ListMovements(limit)
+}
+
+// ListMovementsByReference returns movements booked against a delivery document.
+func (a *App) ListMovementsByReference(reference string) ([]models.StockMovement, error) {
+	if err := a.authorize(models.RoleViewer); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.ListMovementsByReference(reference)
+}
+
+// ListItems returns all items ordered by name.
+func (a *App) ListItems() ([]models.Item, error) {
+	if err := a.authorize(models.RoleViewer); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	var items []models.Item
+	if err := a.db.DB.Preload("Barcodes").Preload("Balances", "quantity <> 0").Order("name asc").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ListLowStock returns items at or below their minimum stock (the reorder list).
+func (a *App) ListLowStock() ([]models.Item, error) {
+	if err := a.authorize(models.RoleViewer); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.ListLowStock()
+}
+
+// FindItemBySKU looks an item up by its SKU.
+func (a *App) FindItemBySKU(sku string) (*models.Item, error) {
+	if err := a.authorize(models.RoleViewer); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.FindItemBySKU(sku)
+}
+
+// FindItemByBarcode looks an item up by one of its barcodes.
+func (a *App) FindItemByBarcode(code string) (*models.Item, error) {
+	if err := a.authorize(models.RoleViewer); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.FindItemByBarcode(code)
+}
+
+// LookupByCode resolves a scanned barcode, SKU or serial number. An unknown
+// code comes back with Found false so the UI can offer to create an item.
+func (a *App) LookupByCode(code string) (models.CodeLookup, error) {
+	if err := a.authorize(models.RoleViewer); err != nil {
+		return models.CodeLookup{}, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return models.CodeLookup{}, fmt.Errorf("database not initialised")
+	}
+	return a.db.LookupByCode(code)
+}
+
+// StartScanSession opens a scan-mode withdrawal or receipt at a location
+// (0 means the default one).
+func (a *App) StartScanSession(kind string, locationID uint, comment string, reference string) (*models.ScanSession, error) {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.StartScanSession(kind, locationID, comment, reference)
+}
+
+// GetScanSession returns a scan session with its lines.
+func (a *App) GetScanSession(id uint) (*models.ScanSession, error) {
+	if err := a.authorize(models.RoleViewer); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.GetScanSession(id)
+}
+
+// ListScanSessions returns scan sessions with a status (open, committed,
+// cancelled), or all when status is empty.
+func (a *App) ListScanSessions(status string) ([]models.ScanSession, error) {
+	if err := a.authorize(models.RoleViewer); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.ListScanSessions(status)
+}
+
+// ScanCode adds a scanned code to an open session; qty 0 means one unit.
+func (a *App) ScanCode(sessionID uint, code string, qty models.Quantity) (*models.ScanResult, error) {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.ScanCode(sessionID, code, qty)
+}
+
+// SetScanLineQuantity corrects a scanned line; quantity 0 removes it.
+func (a *App) SetScanLineQuantity(lineID uint, qty models.Quantity) (*models.ScanSession, error) {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.SetScanLineQuantity(lineID, qty)
+}
+
+// CommitScanSession books all lines of a scan session at once.
+func (a *App) CommitScanSession(id uint) (*models.ScanSession, error) {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	session, err := a.db.CommitScanSession(id)
+	if err != nil {
+		return nil, err
+	}
+	runtime.EventsEmit(a.ctx, "items:changed")
+	return session, nil
+}
+
+// CancelScanSession discards a scan session without booking it.
+func (a *App) CancelScanSession(id uint) (*models.ScanSession, error) {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.CancelScanSession(id)
+}
+
+// StartStocktake opens a physical count of a location (0 means the default
+// one), limited to a category when it is not empty.
+func (a *App) StartStocktake(locationID uint, category string, comment string) (*models.Stocktake, error) {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.StartStocktake(locationID, category, comment)
+}
+
+// GetStocktake returns a stocktake with its lines and variances.
+func (a *App) GetStocktake(id uint) (*models.Stocktake, error) {
+	if err := a.authorize(models.RoleViewer); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.GetStocktake(id)
+}
+
+// ListStocktakes returns stocktakes with a status (open, posted, cancelled),
+// or all when status is empty.
+func (a *App) ListStocktakes(status string) ([]models.Stocktake, error) {
+	if err := a.authorize(models.RoleViewer); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.ListStocktakes(status)
+}
+
+// RecordCount sets the counted quantity of an item; null clears it.
+func (a *App) RecordCount(stocktakeID uint, itemID uint, counted *models.Quantity) (*models.StocktakeLine, error) {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.RecordCount(stocktakeID, itemID, counted)
+}
+
+// PostStocktake books the variances of a stocktake as adjustments.
+func (a *App) PostStocktake(id uint) (*models.Stocktake, error) {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	st, err := a.db.PostStocktake(id)
+	if err != nil {
+		return nil, err
+	}
+	runtime.EventsEmit(a.ctx, "items:changed")
+	return st, nil
+}
+
+// CancelStocktake discards a stocktake without adjusting stock.
+func (a *App) CancelStocktake(id uint) (*models.Stocktake, error) {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.CancelStocktake(id)
+}
+
+// ExportStocktakeReport writes the printable variance report of a
+// stocktake as PDF. When path is empty a native save dialog asks for it.
+// Returns the written path, or an empty string if the user cancelled.
+func (a *App) ExportStocktakeReport(id uint, path string) (string, error) {
+	if err := a.authorize(models.RoleViewer); err != nil {
+		return "", err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return "", fmt.Errorf("database not initialised")
+	}
+	if path == "" {
+		var err error
+		path, err = runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+			Title:           "Сличительная ведомость",
+			DefaultFilename: fmt.Sprintf("инвентаризация-%d-%s.pdf", id, time.Now().Format("2006-01-02")),
+			Filters:         []runtime.FileFilter{{DisplayName: "PDF (*.pdf)", Pattern: "*.pdf"}},
+		})
+		if err != nil || path == "" {
+			return "", err
+		}
+		if filepath.Ext(path) == "" {
+			path += ".pdf"
+		}
+	}
+	if err := a.db.WriteStocktakeReport(id, path); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// ListSuppliers returns all suppliers.
+func (a *App) ListSuppliers() ([]models.Supplier, error) {
+	if err := a.authorize(models.RoleViewer); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.ListSuppliers()
+}
+
+// CreateSupplier adds a supplier.
+func (a *App) CreateSupplier(in models.SupplierInput) (*models.Supplier, error) {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.CreateSupplier(in)
+}
+
+// UpdateSupplier changes a supplier.
+func (a *App) UpdateSupplier(id uint, in models.SupplierInput) (*models.Supplier, error) {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.UpdateSupplier(id, in)
+}
+
+// DeleteSupplier removes a supplier without open orders.
+func (a *App) DeleteSupplier(id uint) error {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return fmt.Errorf("database not initialised")
+	}
+	return a.db.DeleteSupplier(id)
+}
+
+// ListPurchaseOrders returns purchase orders filtered by status (draft, sent,
+// partially_received, closed) and supplier; empty and 0 match all.
+func (a *App) ListPurchaseOrders(status string, supplierID uint) ([]models.PurchaseOrder, error) {
+	if err := a.authorize(models.RoleViewer); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.ListPurchaseOrders(status, supplierID)
+}
+
+// GetPurchaseOrder returns an order with its lines and outstanding quantities.
+func (a *App) GetPurchaseOrder(id uint) (*models.PurchaseOrder, error) {
+	if err := a.authorize(models.RoleViewer); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.GetPurchaseOrder(id)
+}
+
+// CreatePurchaseOrder creates a draft purchase order.
+func (a *App) CreatePurchaseOrder(in models.PurchaseOrderInput) (*models.PurchaseOrder, error) {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.CreatePurchaseOrder(in)
+}
+
+// UpdatePurchaseOrder changes a draft purchase order.
+func (a *App) UpdatePurchaseOrder(id uint, in models.PurchaseOrderInput) (*models.PurchaseOrder, error) {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.UpdatePurchaseOrder(id, in)
+}
+
+// DeletePurchaseOrder removes a draft purchase order.
+func (a *App) DeletePurchaseOrder(id uint) error {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return fmt.Errorf("database not initialised")
+	}
+	return a.db.DeletePurchaseOrder(id)
+}
+
+// SendPurchaseOrder marks a draft order as sent to the supplier.
+func (a *App) SendPurchaseOrder(id uint) (*models.PurchaseOrder, error) {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.SendPurchaseOrder(id)
+}
+
+// ClosePurchaseOrder closes an order that will not be delivered in full.
+func (a *App) ClosePurchaseOrder(id uint) (*models.PurchaseOrder, error) {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.ClosePurchaseOrder(id)
+}
+
+// ReceivePurchaseOrder books goods received against a sent order at a
+// location (0 means the order's location).
+func (a *App) ReceivePurchaseOrder(id uint, locationID uint, receipt []models.ReceiptLine, comment string) (*models.PurchaseOrder, error) {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	order, err := a.db.ReceivePurchaseOrder(id, locationID, receipt, comment)
+	if err != nil {
+		return nil, err
+	}
+	runtime.EventsEmit(a.ctx, "items:changed")
+	return order, nil
+}
+
+// ListIssueRequests returns issue requests filtered by status (pending,
+// approved, issued, rejected); empty matches all.
+func (a *App) ListIssueRequests(status string) ([]models.IssueRequest, error) {
+	if err := a.authorize(models.RoleViewer); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.ListIssueRequests(status)
+}
+
+// GetIssueRequest returns an issue request with its lines.
+func (a *App) GetIssueRequest(id uint) (*models.IssueRequest, error) {
+	if err := a.authorize(models.RoleViewer); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.GetIssueRequest(id)
+}
+
+// CreateIssueRequest registers a pending issue request.
+func (a *App) CreateIssueRequest(in models.IssueRequestInput) (*models.IssueRequest, error) {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.CreateIssueRequest(in)
+}
+
+// UpdateIssueRequest changes a pending issue request.
+func (a *App) UpdateIssueRequest(id uint, in models.IssueRequestInput) (*models.IssueRequest, error) {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.UpdateIssueRequest(id, in)
+}
+
+// ApproveIssueRequest approves a pending request and reserves its stock.
+func (a *App) ApproveIssueRequest(id uint, comment string) (*models.IssueRequest, error) {
+	if err := a.authorize(models.RoleAdmin); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	req, err := a.db.ApproveIssueRequest(id, comment)
+	if err != nil {
+		return nil, err
+	}
+	runtime.EventsEmit(a.ctx, "items:changed")
+	return req, nil
+}
+
+// RejectIssueRequest rejects a pending or approved request, releasing
+// any reservation.
+func (a *App) RejectIssueRequest(id uint, reason string) (*models.IssueRequest, error) {
+	if err := a.authorize(models.RoleAdmin); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	req, err := a.db.RejectIssueRequest(id, reason)
+	if err != nil {
+		return nil, err
+	}
+	runtime.EventsEmit(a.ctx, "items:changed")
+	return req, nil
+}
+
+// FulfillIssueRequest withdraws the stock of an approved request.
+func (a *App) FulfillIssueRequest(id uint, comment string) (*models.IssueRequest, error) {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	req, err := a.db.FulfillIssueRequest(id, comment)
+	if err != nil {
+		return nil, err
+	}
+	runtime.EventsEmit(a.ctx, "items:changed")
+	return req, nil
+}
+
+// ListReservations returns the active reservations of an item (0 for all items).
+func (a *App) ListReservations(itemID uint) ([]models.Reservation, error) {
+	if err := a.authorize(models.RoleViewer); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.ListReservations(itemID)
+}
+
+// CreateReservation holds available stock of an item for someone.
+func (a *App) CreateReservation(in models.ReservationInput) (*models.Reservation, error) {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	r, err := a.db.CreateReservation(in)
+	if err != nil {
+		return nil, err
+	}
+	runtime.EventsEmit(a.ctx, "items:changed")
+	return r, nil
+}
+
+// ReleaseReservation ends a reservation, making its stock available again.
+func (a *App) ReleaseReservation(id uint) error {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return fmt.Errorf("database not initialised")
+	}
+	if err := a.db.ReleaseReservation(id); err != nil {
+		return err
+	}
+	runtime.EventsEmit(a.ctx, "items:changed")
+	return nil
+}
+
+// GetValuationReport returns the value of the stock on hand at asOf (nil
+// for now) per item, with totals per category.
+func (a *App) GetValuationReport(asOf *time.Time) (*models.ValuationReport, error) {
+	if err := a.authorize(models.RoleViewer); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	var at time.Time
+	if asOf != nil {
+		at = *asOf
+	}
+	return a.db.GetValuationReport(at)
+}
+
+// QueryItems returns a filtered, sorted page of items plus the total match count.
+func (a *App) QueryItems(query models.ItemQuery) (models.ItemPage, error) {
+	if err := a.authorize(models.RoleViewer); err != nil {
+		return models.ItemPage{}, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return models.ItemPage{}, fmt.Errorf("database not initialised")
+	}
+	return a.db.QueryItems(query)
+}
+
+// TransferStock moves qty of an item between two locations (0 means the default one).
+func (a *App) TransferStock(itemID uint, from uint, to uint, qty models.Quantity, comment string) (*models.Item, error) {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	item, err := a.db.TransferStock(itemID, from, to, qty, comment)
+	if err != nil {
+		return nil, err
+	}
+	runtime.EventsEmit(a.ctx, "items:changed")
+	return item, nil
+}
+
+// ListItemBalances returns an item's stock per location.
+func (a *App) ListItemBalances(itemID uint) ([]models.StockBalance, error) {
+	if err := a.authorize(models.RoleViewer); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.ListItemBalances(itemID)
+}
+
+// ListLocationStock returns the items held at one location with their quantities there.
+func (a *App) ListLocationStock(locationID uint) ([]models.StockBalance, error) {
+	if err := a.authorize(models.RoleViewer); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.ListLocationStock(locationID)
+}
+
+// ListLocations returns the warehouses / storerooms.
+func (a *App) ListLocations() ([]models.Location, error) {
+	if err := a.authorize(models.RoleViewer); err != nil {
+		return nil, err
 	}
-	a.exePath = exePath
-	a.updater = services.NewUpdaterService(exePath, "nineteenss", "goods_wails_app")
-	// Start background update watcher (check-only; no auto-download/apply)
-	go a.backgroundUpdateLoop()
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.ListLocations()
 }
 
-// Greet returns a greeting for the given name
-func (a *App) Greet(name string) string {
-	return fmt.Sprintf("Hello %s, It's show time!", name)
+// CreateLocation adds a warehouse / storeroom. Admin only.
+func (a *App) CreateLocation(input models.LocationInput) (*models.Location, error) {
+	if err := a.authorize(models.RoleAdmin); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	loc, err := a.db.CreateLocation(input)
+	if err != nil {
+		return nil, err
+	}
+	runtime.EventsEmit(a.ctx, "locations:changed")
+	return loc, nil
 }
 
-// CreateItem creates a new inventory item.
-func (a *App) CreateItem(name string, quantity int, comment string) (*models.Item, error) {
+// UpdateLocation renames a location or makes it the default. Admin only.
+func (a *App) UpdateLocation(id uint, input models.LocationInput) (*models.Location, error) {
+	if err := a.authorize(models.RoleAdmin); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	loc, err := a.db.UpdateLocation(id, input)
+	if err != nil {
+		return nil, err
+	}
+	runtime.EventsEmit(a.ctx, "locations:changed")
+	return loc, nil
+}
+
+// DeleteLocation removes an empty location. Admin only.
+func (a *App) DeleteLocation(id uint) error {
+	if err := a.authorize(models.RoleAdmin); err != nil {
+		return err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return fmt.Errorf("database not initialised")
+	}
+	if err := a.db.DeleteLocation(id); err != nil {
+		return err
+	}
+	runtime.EventsEmit(a.ctx, "locations:changed")
+	return nil
+}
+
+// ReceiveLot receives delta of a lot-tracked item into a lot at a location
+// (0 means the default one) at unitCost per unit (nil for the average cost).
+func (a *App) ReceiveLot(itemID uint, locationID uint, delta models.Quantity, lot models.LotInput, comment string, reference string, unitCost *models.Money) (*models.Item, error) {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return nil, err
+	}
 	if a.db == nil || a.db.DB == nil {
 		return nil, fmt.Errorf("database not initialised")
 	}
-	item := &models.Item{
-		Name:      name,
-		Quantity:  quantity,
-		Comment:   comment,
-		UpdatedAt: time.Now(),
+	item, err := a.db.ReceiveLot(itemID, locationID, delta, lot, comment, reference, unitCost)
+	if err != nil {
+		return nil, err
+	}
+	runtime.EventsEmit(a.ctx, "items:changed")
+	return item, nil
+}
+
+// WithdrawFromLot withdraws delta from the chosen lot instead of the one
+// expiring first.
+func (a *App) WithdrawFromLot(lotID uint, delta models.Quantity, comment string) (*models.Item, error) {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
 	}
-	if err := a.db.DB.Create(item).Error; err != nil {
+	item, err := a.db.WithdrawFromLot(lotID, delta, comment)
+	if err != nil {
 		return nil, err
 	}
 	runtime.EventsEmit(a.ctx, "items:changed")
 	return item, nil
 }
 
-// UpdateItem updates existing item by id.
-func (a *App) UpdateItem(id uint, name string, quantity int, comment string) (*models.Item, error) {
+// ListItemLots returns the lots of an item that hold stock, first to expire first.
+func (a *App) ListItemLots(itemID uint) ([]models.Lot, error) {
+	if err := a.authorize(models.RoleViewer); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.ListItemLots(itemID)
+}
+
+// ListExpiring returns lots expiring within days, already expired ones included.
+func (a *App) ListExpiring(days int) ([]models.Lot, error) {
+	if err := a.authorize(models.RoleViewer); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.ListExpiring(days)
+}
+
+// AddSerials receives one unit of a serial-tracked item per serial number
+// at a location (0 means the default one), each at unitCost (nil for the
+// average cost).
+func (a *App) AddSerials(itemID uint, locationID uint, serials []string, comment string, reference string, unitCost *models.Money) (*models.Item, error) {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return nil, err
+	}
 	if a.db == nil || a.db.DB == nil {
 		return nil, fmt.Errorf("database not initialised")
 	}
-	var item models.Item
-	if err := a.db.DB.First(&item, id).Error; err != nil {
+	item, err := a.db.AddSerials(itemID, locationID, serials, comment, reference, unitCost)
+	if err != nil {
+		return nil, err
+	}
+	runtime.EventsEmit(a.ctx, "items:changed")
+	return item, nil
+}
+
+// EnableSerialTracking turns on serial tracking for an item with stock on
+// hand, one serial number per unit, all at one location (0 for the one
+// holding the stock).
+func (a *App) EnableSerialTracking(itemID uint, locationID uint, serials []string) (*models.Item, error) {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
 		return nil, err
 	}
-	item.Name = name
-	item.Quantity = quantity
-	item.Comment = comment
-	item.UpdatedAt = time.Now()
-	if err := a.db.DB.Save(&item).Error; err != nil {
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	item, err := a.db.EnableSerialTracking(itemID, locationID, serials)
+	if err != nil {
 		return nil, err
 	}
 	runtime.EventsEmit(a.ctx, "items:changed")
-	return &item, nil
+	return item, nil
 }
 
-// WithdrawQuantity decreases quantity for the item by delta (must be positive).
-func (a *App) WithdrawQuantity(id uint, delta int, comment string) (*models.Item, error) {
-	if delta <= 0 {
-		return nil, fmt.Errorf("delta must be positive")
+// IssueSerial hands the unit with this serial number out to issuedTo.
+func (a *App) IssueSerial(serial string, issuedTo string, comment string) (*models.ItemSerial, error) {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return nil, err
 	}
 	if a.db == nil || a.db.DB == nil {
 		return nil, fmt.Errorf("database not initialised")
 	}
-	var item models.Item
-	if err := a.db.DB.First(&item, id).Error; err != nil {
+	unit, err := a.db.IssueSerial(serial, issuedTo, comment)
+	if err != nil {
 		return nil, err
 	}
-	if item.Quantity < delta {
-		return nil, fmt.Errorf("insufficient quantity")
+	runtime.EventsEmit(a.ctx, "items:changed")
+	return unit, nil
+}
+
+// ReturnSerial puts a unit back in stock at a location (0 means the default one).
+func (a *App) ReturnSerial(serial string, locationID uint, comment string) (*models.ItemSerial, error) {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return nil, err
 	}
-	item.Quantity -= delta
-	if comment != "" {
-		item.Comment = comment
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
 	}
-	item.UpdatedAt = time.Now()
-	if err := a.db.DB.Save(&item).Error; err != nil {
+	unit, err := a.db.ReturnSerial(serial, locationID, comment)
+	if err != nil {
 		return nil, err
 	}
 	runtime.EventsEmit(a.ctx, "items:changed")
-	return &item, nil
+	return unit, nil
 }
 
-// ListItems returns all items ordered by name.
-func (a *App) ListItems() ([]models.Item, error) {
+// SetSerialStatus changes the status of a unit: in_stock, issued, in_repair or written_off.
+func (a *App) SetSerialStatus(serial string, status string, comment string) (*models.ItemSerial, error) {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return nil, err
+	}
 	if a.db == nil || a.db.DB == nil {
 		return nil, fmt.Errorf("database not initialised")
 	}
-	var items []models.Item
-	if err := a.db.DB.Order("name asc").Find(&items).Error; err != nil {
+	unit, err := a.db.SetSerialStatus(serial, status, comment)
+	if err != nil {
 		return nil, err
 	}
-	return items, nil
+	runtime.EventsEmit(a.ctx, "items:changed")
+	return unit, nil
 }
 
-// initDatabase resolves an OS-specific config directory and initializes the DB.
-// Separated for clarity and easier testing.
-func initDatabase(a *App) {
-	// Store DB next to the app executable
+// ListItemSerials returns the serial numbers registered for an item.
+func (a *App) ListItemSerials(itemID uint) ([]models.ItemSerial, error) {
+	if err := a.authorize(models.RoleViewer); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.ListItemSerials(itemID)
+}
+
+// FindSerial looks a unit up by its exact serial number.
+func (a *App) FindSerial(serial string) (*models.ItemSerial, error) {
+	if err := a.authorize(models.RoleViewer); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.FindSerial(serial)
+}
+
+// SearchSerials returns up to limit units whose serial number contains query.
+func (a *App) SearchSerials(query string, limit int) ([]models.ItemSerial, error) {
+	if err := a.authorize(models.RoleViewer); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	return a.db.SearchSerials(query, limit)
+}
+
+// SelectImportFile opens a native file dialog for choosing a catalog to import.
+// Returns an empty string when the user cancels.
+func (a *App) SelectImportFile() (string, error) {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return "", err
+	}
+	return runtime.OpenFileDialog(a.ctx, runtime.OpenDialogOptions{
+		Title: "Импорт каталога",
+		Filters: []runtime.FileFilter{
+			{DisplayName: "CSV / Excel (*.csv;*.xlsx)", Pattern: "*.csv;*.xlsx"},
+		},
+	})
+}
+
+// ImportItems imports a CSV or XLSX catalog file. See models.ImportOptions.
+func (a *App) ImportItems(path string, options models.ImportOptions) (models.ImportResult, error) {
+	if err := a.authorize(models.RoleStorekeeper); err != nil {
+		return models.ImportResult{}, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return models.ImportResult{}, fmt.Errorf("database not initialised")
+	}
+	result, err := a.db.ImportItems(path, options)
+	if err != nil {
+		return result, err
+	}
+	if result.Applied {
+		runtime.EventsEmit(a.ctx, "items:changed")
+	}
+	return result, nil
+}
+
+// ExportItems writes the items matching filter to a CSV, XLSX or PDF file.
+// When path is empty a native save dialog asks for it. Returns the written
+// path, or an empty string if the user cancelled the dialog.
+func (a *App) ExportItems(format string, path string, filter models.ItemQuery) (string, error) {
+	if err := a.authorize(models.RoleViewer); err != nil {
+		return "", err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return "", fmt.Errorf("database not initialised")
+	}
+	format = strings.ToLower(format)
+	if path == "" {
+		filters := map[string]runtime.FileFilter{
+			models.FormatCSV:  {DisplayName: "CSV (*.csv)", Pattern: "*.csv"},
+			models.FormatXLSX: {DisplayName: "Excel (*.xlsx)", Pattern: "*.xlsx"},
+			models.FormatPDF:  {DisplayName: "PDF (*.pdf)", Pattern: "*.pdf"},
+		}
+		ff, ok := filters[format]
+		if !ok {
+			return "", fmt.Errorf("unsupported export format %q", format)
+		}
+		var err error
+		path, err = runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+			Title:           "Экспорт остатков",
+			DefaultFilename: fmt.Sprintf("остатки-%s.%s", time.Now().Format("2006-01-02"), format),
+			Filters:         []runtime.FileFilter{ff},
+		})
+		if err != nil || path == "" {
+			return "", err
+		}
+		if filepath.Ext(path) == "" {
+			path += "." + format
+		}
+	}
+	if _, err := a.db.ExportItems(format, path, filter); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// ListLabelPresets returns the predefined label sheets for GenerateLabels.
+func (a *App) ListLabelPresets() ([]models.LabelPreset, error) {
+	if err := a.authorize(models.RoleViewer); err != nil {
+		return nil, err
+	}
+	return models.LabelPresets(), nil
+}
+
+// GenerateLabels renders shelf labels for the items with a native save
+// dialog asking where to put them. Returns the written files, or nothing if
+// the user cancelled the dialog. See models.LabelTemplate.
+func (a *App) GenerateLabels(itemIDs []uint, template models.LabelTemplate) ([]string, error) {
+	if err := a.authorize(models.RoleViewer); err != nil {
+		return nil, err
+	}
+	if a.db == nil || a.db.DB == nil {
+		return nil, fmt.Errorf("database not initialised")
+	}
+	format := strings.ToLower(template.Format)
+	if format == "" {
+		format = models.FormatPDF
+	}
+	filters := map[string]runtime.FileFilter{
+		models.FormatPDF: {DisplayName: "PDF (*.pdf)", Pattern: "*.pdf"},
+		models.FormatPNG: {DisplayName: "PNG (*.png)", Pattern: "*.png"},
+	}
+	ff, ok := filters[format]
+	if !ok {
+		return nil, fmt.Errorf("unsupported label format %q", format)
+	}
+	path, err := runtime.SaveFileDialog(a.ctx, runtime.SaveDialogOptions{
+		Title:           "Сохранить этикетки",
+		DefaultFilename: fmt.Sprintf("этикетки-%s.%s", time.Now().Format("2006-01-02"), format),
+		Filters:         []runtime.FileFilter{ff},
+	})
+	if err != nil || path == "" {
+		return nil, err
+	}
+	if filepath.Ext(path) == "" {
+		path += "." + format
+	}
+	return a.db.GenerateLabels(itemIDs, template, path)
+}
+
+// initDataDir resolves the executable path and the data directory, and
+// loads the settings kept there. It runs before the window is created so
+// the window options can come from the settings.
+func initDataDir(a *App) {
 	exePath, err := os.Executable()
 	if err != nil || exePath == "" {
 		// Fallback: current working directory
 		exePath, _ = os.Getwd()
 	}
-	appDir := filepath.Dir(exePath)
-	if mkErr := os.MkdirAll(appDir, 0o755); mkErr != nil {
-		log.Printf("failed to ensure app dir %s: %v", appDir, mkErr)
+	a.exePath = exePath
+	dataDir, source, err := services.ResolveDataDir(filepath.Dir(exePath), os.Args[1:])
+	if err != nil {
+		log.Printf("failed to resolve data dir: %v", err)
+		a.dbErr = err
+		return
+	}
+	log.Printf("data dir (%s): %s", source, dataDir)
+	if mkErr := os.MkdirAll(dataDir, 0o755); mkErr != nil {
+		log.Printf("failed to ensure data dir %s: %v", dataDir, mkErr)
+		a.dbErr = mkErr
+		return
+	}
+	a.dataDir = dataDir
+	settings, err := services.NewSettingsService(dataDir)
+	if err != nil {
+		// Run on defaults; the file is left untouched for the user to fix.
+		log.Printf("failed to load settings: %v", err)
+		return
+	}
+	a.settings = settings
+}
+
+// initDatabase moves a database left next to the executable by older
+// versions into the data directory, and initializes the DB.
+// Separated for clarity and easier testing.
+func initDatabase(a *App) {
+	if a.dbErr != nil {
+		return
+	}
+	dbFile := a.currentSettings().DatabaseFile
+	if err := services.MigrateLegacyData(filepath.Dir(a.exePath), a.dataDir, dbFile); err != nil {
+		// Keep going on a fresh database would hide the user's data; stop instead.
+		log.Printf("failed to move legacy database: %v", err)
+		a.dbErr = fmt.Errorf("move database to %s: %w", a.dataDir, err)
+		return
 	}
 
-	dbService, err := services.NewDatabaseService(appDir, "inventory.db")
+	dbService, err := services.NewDatabaseService(a.dataDir, dbFile)
 	if err != nil {
 		log.Printf("failed to init db: %v", err)
+		a.dbErr = err
+		return
+	}
+
+	dbService.OnLowStock = func(item models.Item) {
+		runtime.EventsEmit(a.ctx, "stock:low", item)
+	}
+	dbService.Actor = a.currentUser
+
+	// Refuse to work on a database we cannot fully migrate (or that is
+	// newer than this build) rather than run against a half-migrated schema.
+	if err := dbService.Migrate(); err != nil {
+		log.Printf("migrate error: %v", err)
+		a.dbErr = err
 		return
 	}
 	a.db = dbService
+}
+
+// initBackups starts the backup service next to the database: one snapshot
+// now and then one per configured interval while the app runs.
+func initBackups(a *App) {
+	if a.db == nil {
+		return
+	}
+	backups, err := services.NewBackupService(a.db, filepath.Join(a.dataDir, services.BackupDirName))
+	if err != nil {
+		log.Printf("failed to init backups: %v", err)
+		return
+	}
+	cfg := a.currentSettings()
+	backups.Configure(time.Duration(cfg.BackupIntervalHours)*time.Hour, cfg.BackupKeepDaily, cfg.BackupKeepWeekly)
+	a.backups = backups
+	go func() {
+		if _, err := backups.Backup(models.BackupStartup); err != nil {
+			log.Printf("startup backup failed: %v", err)
+		}
+		backups.Run(a.ctx)
+	}()
+}
+
+// currentSettings returns the loaded settings, or the defaults when the data
+// directory could not be used.
+func (a *App) currentSettings() models.Settings {
+	if a.settings == nil {
+		return models.DefaultSettings()
+	}
+	return a.settings.Get()
+}
+
+// GetSettings returns the application settings.
+func (a *App) GetSettings() models.Settings {
+	return a.currentSettings()
+}
+
+// UpdateSettings validates and saves the settings and applies them. The
+// database file name takes effect on the next start.
+func (a *App) UpdateSettings(settings models.Settings) (models.Settings, error) {
+	if err := a.authorize(models.RoleAdmin); err != nil {
+		return a.currentSettings(), err
+	}
+	if a.settings == nil {
+		return a.currentSettings(), fmt.Errorf("settings not initialised")
+	}
+	return a.settings.Update(settings)
+}
+
+// applySettings reacts to a settings change and emits "settings:changed".
+func (a *App) applySettings(old, cur models.Settings) {
+	if old.UpdateRepoOwner != cur.UpdateRepoOwner || old.UpdateRepoName != cur.UpdateRepoName {
+		a.updater.SetRepo(cur.UpdateRepoOwner, cur.UpdateRepoName)
+		a.latestTag, a.latestAssetURL, a.downloaded = "", "", false
+	}
+	if old.UpdateCheckIntervalHours != cur.UpdateCheckIntervalHours || old.UpdateInitialDelaySeconds != cur.UpdateInitialDelaySeconds {
+		select {
+		case a.updateWake <- struct{}{}:
+		default:
+		}
+	}
+	if a.backups != nil && (old.BackupIntervalHours != cur.BackupIntervalHours ||
+		old.BackupKeepDaily != cur.BackupKeepDaily || old.BackupKeepWeekly != cur.BackupKeepWeekly) {
+		a.backups.Configure(time.Duration(cur.BackupIntervalHours)*time.Hour, cur.BackupKeepDaily, cur.BackupKeepWeekly)
+	}
+	if old.WindowWidth != cur.WindowWidth || old.WindowHeight != cur.WindowHeight {
+		runtime.WindowSetSize(a.ctx, cur.WindowWidth, cur.WindowHeight)
+	}
+	runtime.EventsEmit(a.ctx, "settings:changed", cur)
+}
+
+// GetDataDir returns the folder holding the database and backups.
+func (a *App) GetDataDir() string {
+	return a.dataDir
+}
+
+// ListBackups returns the available database backups, newest first.
+func (a *App) ListBackups() ([]models.BackupInfo, error) {
+	if err := a.authorize(models.RoleAdmin); err != nil {
+		return nil, err
+	}
+	if a.backups == nil {
+		return nil, fmt.Errorf("backups not initialised")
+	}
+	return a.backups.List()
+}
+
+// BackupNow takes a manual database backup.
+func (a *App) BackupNow() (models.BackupInfo, error) {
+	if err := a.authorize(models.RoleAdmin); err != nil {
+		return models.BackupInfo{}, err
+	}
+	if a.backups == nil {
+		return models.BackupInfo{}, fmt.Errorf("backups not initialised")
+	}
+	return a.backups.Backup(models.BackupManual)
+}
 
-	if err := a.db.DB.AutoMigrate(&models.Item{}); err != nil {
-		log.Printf("auto migrate error: %v", err)
+// RestoreBackup replaces the database with the named backup and quits the
+// app; the backup takes over when the app is started again. The current
+// state is backed up first, so a restore can itself be undone.
+func (a *App) RestoreBackup(name string) error {
+	if err := a.authorize(models.RoleAdmin); err != nil {
+		return err
 	}
+	if a.backups == nil {
+		return fmt.Errorf("backups not initialised")
+	}
+	if err := a.backups.Restore(name); err != nil {
+		return err
+	}
+	go func() {
+		// slight delay to allow response to return
+		time.Sleep(200 * time.Millisecond)
+		runtime.Quit(a.ctx)
+	}()
+	return nil
 }
 
 // SetCurrentVersion sets the current app version (provided by the frontend package.json)
@@ -190,6 +1532,9 @@ func (a *App) CheckForUpdates(currentVersion string) (models.UpdateStatus, error
 
 // DownloadUpdate downloads the latest release asset in the background.
 func (a *App) DownloadUpdate() (models.UpdateStatus, error) {
+	if err := a.authorize(models.RoleAdmin); err != nil {
+		return models.UpdateStatus{}, err
+	}
 	if a.updater == nil {
 		return models.UpdateStatus{CurrentVersion: a.currentVersion, Error: "updater not initialised"}, nil
 	}
@@ -217,9 +1562,18 @@ func (a *App) DownloadUpdate() (models.UpdateStatus, error) {
 
 // ApplyAndRestart will replace the executable with the downloaded one and relaunch the app.
 func (a *App) ApplyAndRestart() error {
+	if err := a.authorize(models.RoleAdmin); err != nil {
+		return err
+	}
 	if a.updater == nil {
 		return fmt.Errorf("updater not initialised")
 	}
+	// Never swap binaries without a fresh copy of the data.
+	if a.backups != nil {
+		if _, err := a.backups.Backup(models.BackupPreUpdate); err != nil {
+			return fmt.Errorf("backup before update: %w", err)
+		}
+	}
 	if err := a.updater.PlanApplyOnExit(); err != nil {
 		return err
 	}
@@ -232,17 +1586,47 @@ func (a *App) ApplyAndRestart() error {
 	return nil
 }
 
+// reservationExpiryLoop releases expired reservations now and then every
+// minute while the app runs, telling the frontend which ones lapsed.
+func (a *App) reservationExpiryLoop() {
+	for {
+		if a.db != nil {
+			expired, err := a.db.ExpireReservations()
+			if err != nil {
+				log.Printf("expire reservations: %v", err)
+			} else if len(expired) > 0 {
+				runtime.EventsEmit(a.ctx, "reservations:expired", expired)
+				runtime.EventsEmit(a.ctx, "items:changed")
+			}
+		}
+		select {
+		case <-time.After(time.Minute):
+		case <-a.ctx.Done():
+			return
+		}
+	}
+}
+
 // backgroundUpdateLoop periodically checks for updates and downloads them silently.
+// The delay before the first check and the interval come from the settings;
+// a change restarts the current wait.
 func (a *App) backgroundUpdateLoop() {
-	ticker := time.NewTicker(6 * time.Hour)
-	defer ticker.Stop()
+	first := true
 	for {
-		// Do an initial short delay to avoid competing with startup
+		cfg := a.currentSettings()
+		wait := time.Duration(cfg.UpdateCheckIntervalHours) * time.Hour
+		if first {
+			// Do an initial short delay to avoid competing with startup
+			wait = time.Duration(cfg.UpdateInitialDelaySeconds) * time.Second
+		}
 		select {
-		case <-time.After(30 * time.Second):
+		case <-time.After(wait):
+		case <-a.updateWake:
+			continue
 		case <-a.ctx.Done():
 			return
 		}
+		first = false
 
 		if a.currentVersion != "" && a.updater != nil {
 			tag, assetURL, err := a.updater.CheckLatest(a.ctx)
@@ -253,12 +1637,5 @@ func (a *App) backgroundUpdateLoop() {
 				// No auto-download/apply. The user must click the button to download and apply.
 			}
 		}
-
-		select {
-		case <-ticker.C:
-			continue
-		case <-a.ctx.Done():
-			return
-		}
 	}
 }
@@ -4,13 +4,23 @@ import { Providers } from "./Components/Providers/Providers";
 import { Toolbar } from "./Components/Toolbar/Toolbar";
 import { Table } from "./Components/Table/Table";
 import { CurrentVersion } from "./Components/Version/CurrentVersion";
+import { LoginScreen } from "./Components/Auth/LoginScreen";
+import { UserMenu } from "./Components/Auth/UserMenu";
 import { AmountCounter } from "./Components/AmountCouter/AmountCounter";
 import { Progress } from "@mantine/core";
 import { useEffect, useState } from "react";
 import { EventsOn } from "../wailsjs/runtime/runtime";
+import { currentUser } from "./utils/api";
 
 function App() {
   const [downloadPct, setDownloadPct] = useState(null);
+  const [user, setUser] = useState(null);
+
+  useEffect(() => {
+    currentUser().then(setUser).catch(() => setUser(null));
+    const off = EventsOn("auth:changed", (u) => setUser(u));
+    return () => off && off();
+  }, []);
 
   useEffect(() => {
     const off = EventsOn("update:progress", (downloaded, total) => {
@@ -31,6 +41,14 @@ function App() {
     return () => off && off();
   }, []);
 
+  if (!user) {
+    return (
+      <Providers>
+        <LoginScreen onLogin={setUser} />
+      </Providers>
+    );
+  }
+
   return (
     <Providers>
       <Flex direction={"column"} gap={30} justify={"space-between"} h={"100%"}>
@@ -40,7 +58,10 @@ function App() {
         </Flex>
         <Flex direction={"row"} gap={30} justify={"space-between"}>
           <AmountCounter positions={10} items={100} />
-          <CurrentVersion />
+          <Flex direction={"row"} gap={30} align={"center"}>
+            <UserMenu user={user} onLogout={() => setUser(null)} />
+            <CurrentVersion />
+          </Flex>
         </Flex>
         {downloadPct !== null && (
           <Progress
@@ -0,0 +1,77 @@
+import { useEffect, useState } from "react";
+import { Button, Center, Paper, PasswordInput, Text, TextInput } from "@mantine/core";
+import { useForm } from "@mantine/form";
+
+import { login, needsSetup, setupAdmin, type User } from "../../utils/api";
+
+type Props = {
+  onLogin: (user: User) => void;
+};
+
+// LoginScreen asks for credentials, or for the first admin account when
+// no users exist yet.
+export const LoginScreen = ({ onLogin }: Props) => {
+  const [setup, setSetup] = useState(false);
+  const [error, setError] = useState<string | null>(null);
+  const [busy, setBusy] = useState(false);
+
+  useEffect(() => {
+    needsSetup()
+      .then(setSetup)
+      .catch((e) => setError(String(e)));
+  }, []);
+
+  const form = useForm({
+    initialValues: { username: "", password: "", confirm: "" },
+    validate: {
+      username: (v: string) => (!v.trim() ? "Введите имя пользователя" : null),
+      password: (v: string) => (!v ? "Введите пароль" : null),
+      confirm: (v: string, values) =>
+        setup && v !== values.password ? "Пароли не совпадают" : null,
+    },
+  });
+
+  const submit = async (values: { username: string; password: string }) => {
+    setBusy(true);
+    setError(null);
+    try {
+      const user = setup
+        ? await setupAdmin(values.username, values.password)
+        : await login(values.username, values.password);
+      onLogin(user);
+    } catch (e) {
+      setError(String(e));
+    } finally {
+      setBusy(false);
+    }
+  };
+
+  return (
+    <Center h={"100%"}>
+      <Paper withBorder p="xl" w={360}>
+        <form onSubmit={form.onSubmit(submit)}>
+          <Text size="lg" fw={600} mb="md">
+            {setup ? "Создание администратора" : "Вход в систему"}
+          </Text>
+          <TextInput label="Имя пользователя" {...form.getInputProps("username")} />
+          <PasswordInput mt="sm" label="Пароль" {...form.getInputProps("password")} />
+          {setup && (
+            <PasswordInput
+              mt="sm"
+              label="Повторите пароль"
+              {...form.getInputProps("confirm")}
+            />
+          )}
+          {error && (
+            <Text c="red" size="sm" mt="sm">
+              {error}
+            </Text>
+          )}
+          <Button type="submit" fullWidth mt="lg" loading={busy}>
+            {setup ? "Создать и войти" : "Войти"}
+          </Button>
+        </form>
+      </Paper>
+    </Center>
+  );
+};
@@ -0,0 +1,49 @@
+import { useState } from "react";
+import { Button, Flex, Text, Tooltip } from "@mantine/core";
+import { IconLogout } from "@tabler/icons-react";
+
+import { logout, type User } from "../../utils/api";
+
+type Props = {
+  user: User;
+  onLogout: () => void;
+};
+
+const roleLabels: Record<User["role"], string> = {
+  viewer: "Просмотр",
+  storekeeper: "Кладовщик",
+  admin: "Администратор",
+};
+
+// UserMenu shows who is logged in and ends the session.
+export const UserMenu = ({ user, onLogout }: Props) => {
+  const [busy, setBusy] = useState(false);
+
+  const submit = async () => {
+    setBusy(true);
+    try {
+      await logout();
+      onLogout();
+    } finally {
+      setBusy(false);
+    }
+  };
+
+  return (
+    <Flex direction={"row"} gap={6} align={"center"}>
+      <Tooltip label={roleLabels[user.role]}>
+        <Text size="sm">{user.displayName || user.username}</Text>
+      </Tooltip>
+      <Button
+        size="compact-sm"
+        variant="subtle"
+        color="gray"
+        loading={busy}
+        rightSection={<IconLogout size={18} />}
+        onClick={submit}
+      >
+        Выйти
+      </Button>
+    </Flex>
+  );
+};
@@ -6,52 +6,71 @@ type Item = {
   name: string;
   quantity: number;
   comment: string;
+  sku: string;
+  unit: string;
+  category: string;
+  location: string;
+  barcodes: { id: number; itemId: number; code: string }[];
   updated: string;
 };
 
+// Catalog fields are optional: omitted ones keep their stored values.
+type ItemCatalogFields = {
+  sku?: string;
+  barcodes?: string[];
+  unit?: string;
+  category?: string;
+  location?: string;
+  // Storeroom a quantity change is booked at; required once the stock
+  // is held at several locations.
+  stockLocationId?: number;
+};
+
 export async function listItems(): Promise<Item[]> {
   // @ts-ignore - Wails injects window.go
   return await window.go.main.App.ListItems();
 }
 
-export async function createItem(payload: {
-  name: string;
-  quantity: number;
-  comment: string;
-}): Promise<Item> {
+export async function createItem(
+  payload: {
+    name: string;
+    quantity: number;
+    comment: string;
+  } & ItemCatalogFields,
+): Promise<Item> {
   // @ts-ignore
-  return await window.go.main.App.CreateItem(
-    payload.name,
-    payload.quantity,
-    payload.comment,
-  );
+  return await window.go.main.App.CreateItem(payload);
 }
 
-export async function updateItem(payload: {
-  id: number;
-  name: string;
-  quantity: number;
-  comment: string;
-}): Promise<Item> {
+export async function updateItem(
+  payload: {
+    id: number;
+    name: string;
+    quantity: number;
+    comment: string;
+  } & ItemCatalogFields,
+): Promise<Item> {
+  const { id, ...input } = payload;
   // @ts-ignore
-  return await window.go.main.App.UpdateItem(
-    payload.id,
-    payload.name,
-    payload.quantity,
-    payload.comment,
-  );
+  return await window.go.main.App.UpdateItem(id, input);
 }
 
+// locationId 0 (or omitted) withdraws from the default location.
+// reservationId withdraws stock held by that reservation.
 export async function withdrawItem(payload: {
   id: number;
+  locationId?: number;
   delta: number;
   comment: string;
+  reservationId?: number;
 }): Promise<Item> {
   // @ts-ignore
   return await window.go.main.App.WithdrawQuantity(
     payload.id,
+    payload.locationId ?? 0,
     payload.delta,
     payload.comment,
+    payload.reservationId ?? 0,
   );
 }
 
@@ -81,3 +100,38 @@ export async function applyAndRestart(): Promise<void> {
   return await window.go.main.App.ApplyAndRestart();
 }
 
+
+// Accounts API
+export type User = {
+  id: number;
+  username: string;
+  displayName: string;
+  role: "viewer" | "storekeeper" | "admin";
+  disabled: boolean;
+  lastLogin?: string;
+};
+
+export async function needsSetup(): Promise<boolean> {
+  // @ts-ignore
+  return await window.go.main.App.NeedsSetup();
+}
+
+export async function setupAdmin(username: string, password: string): Promise<User> {
+  // @ts-ignore
+  return await window.go.main.App.SetupAdmin(username, password);
+}
+
+export async function login(username: string, password: string): Promise<User> {
+  // @ts-ignore
+  return await window.go.main.App.Login(username, password);
+}
+
+export async function logout(): Promise<void> {
+  // @ts-ignore
+  return await window.go.main.App.Logout();
+}
+
+export async function currentUser(): Promise<User | null> {
+  // @ts-ignore
+  return await window.go.main.App.CurrentUser();
+}
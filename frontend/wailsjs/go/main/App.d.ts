@@ -1,21 +1,200 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 import {models} from '../models';
+import {time} from '../models';
+
+export function AddSerials(arg1:number,arg2:number,arg3:Array<string>,arg4:string,arg5:string,arg6:models.Money):Promise<models.Item>;
 
 export function ApplyAndRestart():Promise<void>;
 
+export function ApproveIssueRequest(arg1:number,arg2:string):Promise<models.IssueRequest>;
+
+export function BackupNow():Promise<models.BackupInfo>;
+
+export function CancelScanSession(arg1:number):Promise<models.ScanSession>;
+
+export function CancelStocktake(arg1:number):Promise<models.Stocktake>;
+
+export function ChangePassword(arg1:string,arg2:string):Promise<void>;
+
 export function CheckForUpdates(arg1:string):Promise<models.UpdateStatus>;
 
-export function CreateItem(arg1:string,arg2:number,arg3:string):Promise<models.Item>;
+export function ClosePurchaseOrder(arg1:number):Promise<models.PurchaseOrder>;
+
+export function CommitScanSession(arg1:number):Promise<models.ScanSession>;
+
+export function CreateIssueRequest(arg1:models.IssueRequestInput):Promise<models.IssueRequest>;
+
+export function CreateItem(arg1:models.ItemInput):Promise<models.Item>;
+
+export function CreateLocation(arg1:models.LocationInput):Promise<models.Location>;
+
+export function CreatePurchaseOrder(arg1:models.PurchaseOrderInput):Promise<models.PurchaseOrder>;
+
+export function CreateReservation(arg1:models.ReservationInput):Promise<models.Reservation>;
+
+export function CreateSupplier(arg1:models.SupplierInput):Promise<models.Supplier>;
+
+export function CreateUser(arg1:models.UserInput):Promise<models.User>;
+
+export function CurrentUser():Promise<models.User>;
+
+export function DatabaseError():Promise<string>;
+
+export function DeleteItem(arg1:number):Promise<void>;
+
+export function DeleteLocation(arg1:number):Promise<void>;
+
+export function DeletePurchaseOrder(arg1:number):Promise<void>;
+
+export function DeleteSupplier(arg1:number):Promise<void>;
 
 export function DownloadUpdate():Promise<models.UpdateStatus>;
 
+export function EnableSerialTracking(arg1:number,arg2:number,arg3:Array<string>):Promise<models.Item>;
+
+export function ExportItems(arg1:string,arg2:string,arg3:models.ItemQuery):Promise<string>;
+
+export function ExportStocktakeReport(arg1:number,arg2:string):Promise<string>;
+
+export function FindItemByBarcode(arg1:string):Promise<models.Item>;
+
+export function FindItemBySKU(arg1:string):Promise<models.Item>;
+
+export function FindSerial(arg1:string):Promise<models.ItemSerial>;
+
+export function FulfillIssueRequest(arg1:number,arg2:string):Promise<models.IssueRequest>;
+
+export function GenerateLabels(arg1:Array<number>,arg2:models.LabelTemplate):Promise<Array<string>>;
+
+export function GetDataDir():Promise<string>;
+
+export function GetIssueRequest(arg1:number):Promise<models.IssueRequest>;
+
+export function GetPurchaseOrder(arg1:number):Promise<models.PurchaseOrder>;
+
+export function GetScanSession(arg1:number):Promise<models.ScanSession>;
+
+export function GetSettings():Promise<models.Settings>;
+
+export function GetStocktake(arg1:number):Promise<models.Stocktake>;
+
+export function GetValuationReport(arg1:time.Time):Promise<models.ValuationReport>;
+
 export function Greet(arg1:string):Promise<string>;
 
+export function ImportItems(arg1:string,arg2:models.ImportOptions):Promise<models.ImportResult>;
+
+export function IssueSerial(arg1:string,arg2:string,arg3:string):Promise<models.ItemSerial>;
+
+export function ListBackups():Promise<Array<models.BackupInfo>>;
+
+export function ListDeletedItems():Promise<Array<models.Item>>;
+
+export function ListExpiring(arg1:number):Promise<Array<models.Lot>>;
+
+export function ListIssueRequests(arg1:string):Promise<Array<models.IssueRequest>>;
+
+export function ListItemBalances(arg1:number):Promise<Array<models.StockBalance>>;
+
+export function ListItemLots(arg1:number):Promise<Array<models.Lot>>;
+
+export function ListItemMovements(arg1:number):Promise<Array<models.StockMovement>>;
+
+export function ListItemSerials(arg1:number):Promise<Array<models.ItemSerial>>;
+
 export function ListItems():Promise<Array<models.Item>>;
 
+export function ListLabelPresets():Promise<Array<models.LabelPreset>>;
+
+export function ListLocationStock(arg1:number):Promise<Array<models.StockBalance>>;
+
+export function ListLocations():Promise<Array<models.Location>>;
+
+export function ListLowStock():Promise<Array<models.Item>>;
+
+export function ListMovements(arg1:number):Promise<Array<models.StockMovement>>;
+
+export function ListMovementsByReference(arg1:string):Promise<Array<models.StockMovement>>;
+
+export function ListPurchaseOrders(arg1:string,arg2:number):Promise<Array<models.PurchaseOrder>>;
+
+export function ListReservations(arg1:number):Promise<Array<models.Reservation>>;
+
+export function ListScanSessions(arg1:string):Promise<Array<models.ScanSession>>;
+
+export function ListStocktakes(arg1:string):Promise<Array<models.Stocktake>>;
+
+export function ListSuppliers():Promise<Array<models.Supplier>>;
+
+export function ListUsers():Promise<Array<models.User>>;
+
+export function Login(arg1:string,arg2:string):Promise<models.User>;
+
+export function Logout():Promise<void>;
+
+export function LookupByCode(arg1:string):Promise<models.CodeLookup>;
+
+export function NeedsSetup():Promise<boolean>;
+
+export function PostStocktake(arg1:number):Promise<models.Stocktake>;
+
+export function QueryItems(arg1:models.ItemQuery):Promise<models.ItemPage>;
+
+export function ReceiveLot(arg1:number,arg2:number,arg3:models.Quantity,arg4:models.LotInput,arg5:string,arg6:string,arg7:models.Money):Promise<models.Item>;
+
+export function ReceivePurchaseOrder(arg1:number,arg2:number,arg3:Array<models.ReceiptLine>,arg4:string):Promise<models.PurchaseOrder>;
+
+export function ReceiveQuantity(arg1:number,arg2:number,arg3:models.Quantity,arg4:string,arg5:string,arg6:models.Money):Promise<models.Item>;
+
+export function RecordCount(arg1:number,arg2:number,arg3:models.Quantity):Promise<models.StocktakeLine>;
+
+export function RejectIssueRequest(arg1:number,arg2:string):Promise<models.IssueRequest>;
+
+export function ReleaseReservation(arg1:number):Promise<void>;
+
+export function RestoreBackup(arg1:string):Promise<void>;
+
+export function RestoreItem(arg1:number):Promise<models.Item>;
+
+export function ReturnSerial(arg1:string,arg2:number,arg3:string):Promise<models.ItemSerial>;
+
+export function ScanCode(arg1:number,arg2:string,arg3:models.Quantity):Promise<models.ScanResult>;
+
+export function SearchSerials(arg1:string,arg2:number):Promise<Array<models.ItemSerial>>;
+
+export function SelectImportFile():Promise<string>;
+
+export function SendPurchaseOrder(arg1:number):Promise<models.PurchaseOrder>;
+
 export function SetCurrentVersion(arg1:string):Promise<void>;
 
-export function UpdateItem(arg1:number,arg2:string,arg3:number,arg4:string):Promise<models.Item>;
+export function SetScanLineQuantity(arg1:number,arg2:models.Quantity):Promise<models.ScanSession>;
+
+export function SetSerialStatus(arg1:string,arg2:string,arg3:string):Promise<models.ItemSerial>;
+
+export function SetupAdmin(arg1:string,arg2:string):Promise<models.User>;
+
+export function StartScanSession(arg1:string,arg2:number,arg3:string,arg4:string):Promise<models.ScanSession>;
+
+export function StartStocktake(arg1:number,arg2:string,arg3:string):Promise<models.Stocktake>;
+
+export function TransferStock(arg1:number,arg2:number,arg3:number,arg4:models.Quantity,arg5:string):Promise<models.Item>;
+
+export function UpdateIssueRequest(arg1:number,arg2:models.IssueRequestInput):Promise<models.IssueRequest>;
+
+export function UpdateItem(arg1:number,arg2:models.ItemInput):Promise<models.Item>;
+
+export function UpdateLocation(arg1:number,arg2:models.LocationInput):Promise<models.Location>;
+
+export function UpdatePurchaseOrder(arg1:number,arg2:models.PurchaseOrderInput):Promise<models.PurchaseOrder>;
+
+export function UpdateSettings(arg1:models.Settings):Promise<models.Settings>;
+
+export function UpdateSupplier(arg1:number,arg2:models.SupplierInput):Promise<models.Supplier>;
+
+export function UpdateUser(arg1:number,arg2:models.UserInput):Promise<models.User>;
+
+export function WithdrawFromLot(arg1:number,arg2:models.Quantity,arg3:string):Promise<models.Item>;
 
-export function WithdrawQuantity(arg1:number,arg2:number,arg3:string):Promise<models.Item>;
+export function WithdrawQuantity(arg1:number,arg2:number,arg3:models.Quantity,arg4:string,arg5:number):Promise<models.Item>;
@@ -2,38 +2,394 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+export function AddSerials(arg1, arg2, arg3, arg4, arg5, arg6) {
+  return window['go']['main']['App']['AddSerials'](arg1, arg2, arg3, arg4, arg5, arg6);
+}
+
 export function ApplyAndRestart() {
   return window['go']['main']['App']['ApplyAndRestart']();
 }
 
+export function ApproveIssueRequest(arg1, arg2) {
+  return window['go']['main']['App']['ApproveIssueRequest'](arg1, arg2);
+}
+
+export function BackupNow() {
+  return window['go']['main']['App']['BackupNow']();
+}
+
+export function CancelScanSession(arg1) {
+  return window['go']['main']['App']['CancelScanSession'](arg1);
+}
+
+export function CancelStocktake(arg1) {
+  return window['go']['main']['App']['CancelStocktake'](arg1);
+}
+
+export function ChangePassword(arg1, arg2) {
+  return window['go']['main']['App']['ChangePassword'](arg1, arg2);
+}
+
 export function CheckForUpdates(arg1) {
   return window['go']['main']['App']['CheckForUpdates'](arg1);
 }
 
-export function CreateItem(arg1, arg2, arg3) {
-  return window['go']['main']['App']['CreateItem'](arg1, arg2, arg3);
+export function ClosePurchaseOrder(arg1) {
+  return window['go']['main']['App']['ClosePurchaseOrder'](arg1);
+}
+
+export function CommitScanSession(arg1) {
+  return window['go']['main']['App']['CommitScanSession'](arg1);
+}
+
+export function CreateIssueRequest(arg1) {
+  return window['go']['main']['App']['CreateIssueRequest'](arg1);
+}
+
+export function CreateItem(arg1) {
+  return window['go']['main']['App']['CreateItem'](arg1);
+}
+
+export function CreateLocation(arg1) {
+  return window['go']['main']['App']['CreateLocation'](arg1);
+}
+
+export function CreatePurchaseOrder(arg1) {
+  return window['go']['main']['App']['CreatePurchaseOrder'](arg1);
+}
+
+export function CreateReservation(arg1) {
+  return window['go']['main']['App']['CreateReservation'](arg1);
+}
+
+export function CreateSupplier(arg1) {
+  return window['go']['main']['App']['CreateSupplier'](arg1);
+}
+
+export function CreateUser(arg1) {
+  return window['go']['main']['App']['CreateUser'](arg1);
+}
+
+export function CurrentUser() {
+  return window['go']['main']['App']['CurrentUser']();
+}
+
+export function DatabaseError() {
+  return window['go']['main']['App']['DatabaseError']();
+}
+
+export function DeleteItem(arg1) {
+  return window['go']['main']['App']['DeleteItem'](arg1);
+}
+
+export function DeleteLocation(arg1) {
+  return window['go']['main']['App']['DeleteLocation'](arg1);
+}
+
+export function DeletePurchaseOrder(arg1) {
+  return window['go']['main']['App']['DeletePurchaseOrder'](arg1);
+}
+
+export function DeleteSupplier(arg1) {
+  return window['go']['main']['App']['DeleteSupplier'](arg1);
 }
 
 export function DownloadUpdate() {
   return window['go']['main']['App']['DownloadUpdate']();
 }
 
+export function EnableSerialTracking(arg1, arg2, arg3) {
+  return window['go']['main']['App']['EnableSerialTracking'](arg1, arg2, arg3);
+}
+
+export function ExportItems(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ExportItems'](arg1, arg2, arg3);
+}
+
+export function ExportStocktakeReport(arg1, arg2) {
+  return window['go']['main']['App']['ExportStocktakeReport'](arg1, arg2);
+}
+
+export function FindItemByBarcode(arg1) {
+  return window['go']['main']['App']['FindItemByBarcode'](arg1);
+}
+
+export function FindItemBySKU(arg1) {
+  return window['go']['main']['App']['FindItemBySKU'](arg1);
+}
+
+export function FindSerial(arg1) {
+  return window['go']['main']['App']['FindSerial'](arg1);
+}
+
+export function FulfillIssueRequest(arg1, arg2) {
+  return window['go']['main']['App']['FulfillIssueRequest'](arg1, arg2);
+}
+
+export function GenerateLabels(arg1, arg2) {
+  return window['go']['main']['App']['GenerateLabels'](arg1, arg2);
+}
+
+export function GetDataDir() {
+  return window['go']['main']['App']['GetDataDir']();
+}
+
+export function GetIssueRequest(arg1) {
+  return window['go']['main']['App']['GetIssueRequest'](arg1);
+}
+
+export function GetPurchaseOrder(arg1) {
+  return window['go']['main']['App']['GetPurchaseOrder'](arg1);
+}
+
+export function GetScanSession(arg1) {
+  return window['go']['main']['App']['GetScanSession'](arg1);
+}
+
+export function GetSettings() {
+  return window['go']['main']['App']['GetSettings']();
+}
+
+export function GetStocktake(arg1) {
+  return window['go']['main']['App']['GetStocktake'](arg1);
+}
+
+export function GetValuationReport(arg1) {
+  return window['go']['main']['App']['GetValuationReport'](arg1);
+}
+
 export function Greet(arg1) {
   return window['go']['main']['App']['Greet'](arg1);
 }
 
+export function ImportItems(arg1, arg2) {
+  return window['go']['main']['App']['ImportItems'](arg1, arg2);
+}
+
+export function IssueSerial(arg1, arg2, arg3) {
+  return window['go']['main']['App']['IssueSerial'](arg1, arg2, arg3);
+}
+
+export function ListBackups() {
+  return window['go']['main']['App']['ListBackups']();
+}
+
+export function ListDeletedItems() {
+  return window['go']['main']['App']['ListDeletedItems']();
+}
+
+export function ListExpiring(arg1) {
+  return window['go']['main']['App']['ListExpiring'](arg1);
+}
+
+export function ListIssueRequests(arg1) {
+  return window['go']['main']['App']['ListIssueRequests'](arg1);
+}
+
+export function ListItemBalances(arg1) {
+  return window['go']['main']['App']['ListItemBalances'](arg1);
+}
+
+export function ListItemLots(arg1) {
+  return window['go']['main']['App']['ListItemLots'](arg1);
+}
+
+export function ListItemMovements(arg1) {
+  return window['go']['main']['App']['ListItemMovements'](arg1);
+}
+
+export function ListItemSerials(arg1) {
+  return window['go']['main']['App']['ListItemSerials'](arg1);
+}
+
 export function ListItems() {
   return window['go']['main']['App']['ListItems']();
 }
 
+export function ListLabelPresets() {
+  return window['go']['main']['App']['ListLabelPresets']();
+}
+
+export function ListLocationStock(arg1) {
+  return window['go']['main']['App']['ListLocationStock'](arg1);
+}
+
+export function ListLocations() {
+  return window['go']['main']['App']['ListLocations']();
+}
+
+export function ListLowStock() {
+  return window['go']['main']['App']['ListLowStock']();
+}
+
+export function ListMovements(arg1) {
+  return window['go']['main']['App']['ListMovements'](arg1);
+}
+
+export function ListMovementsByReference(arg1) {
+  return window['go']['main']['App']['ListMovementsByReference'](arg1);
+}
+
+export function ListPurchaseOrders(arg1, arg2) {
+  return window['go']['main']['App']['ListPurchaseOrders'](arg1, arg2);
+}
+
+export function ListReservations(arg1) {
+  return window['go']['main']['App']['ListReservations'](arg1);
+}
+
+export function ListScanSessions(arg1) {
+  return window['go']['main']['App']['ListScanSessions'](arg1);
+}
+
+export function ListStocktakes(arg1) {
+  return window['go']['main']['App']['ListStocktakes'](arg1);
+}
+
+export function ListSuppliers() {
+  return window['go']['main']['App']['ListSuppliers']();
+}
+
+export function ListUsers() {
+  return window['go']['main']['App']['ListUsers']();
+}
+
+export function Login(arg1, arg2) {
+  return window['go']['main']['App']['Login'](arg1, arg2);
+}
+
+export function Logout() {
+  return window['go']['main']['App']['Logout']();
+}
+
+export function LookupByCode(arg1) {
+  return window['go']['main']['App']['LookupByCode'](arg1);
+}
+
+export function NeedsSetup() {
+  return window['go']['main']['App']['NeedsSetup']();
+}
+
+export function PostStocktake(arg1) {
+  return window['go']['main']['App']['PostStocktake'](arg1);
+}
+
+export function QueryItems(arg1) {
+  return window['go']['main']['App']['QueryItems'](arg1);
+}
+
+export function ReceiveLot(arg1, arg2, arg3, arg4, arg5, arg6, arg7) {
+  return window['go']['main']['App']['ReceiveLot'](arg1, arg2, arg3, arg4, arg5, arg6, arg7);
+}
+
+export function ReceivePurchaseOrder(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['ReceivePurchaseOrder'](arg1, arg2, arg3, arg4);
+}
+
+export function ReceiveQuantity(arg1, arg2, arg3, arg4, arg5, arg6) {
+  return window['go']['main']['App']['ReceiveQuantity'](arg1, arg2, arg3, arg4, arg5, arg6);
+}
+
+export function RecordCount(arg1, arg2, arg3) {
+  return window['go']['main']['App']['RecordCount'](arg1, arg2, arg3);
+}
+
+export function RejectIssueRequest(arg1, arg2) {
+  return window['go']['main']['App']['RejectIssueRequest'](arg1, arg2);
+}
+
+export function ReleaseReservation(arg1) {
+  return window['go']['main']['App']['ReleaseReservation'](arg1);
+}
+
+export function RestoreBackup(arg1) {
+  return window['go']['main']['App']['RestoreBackup'](arg1);
+}
+
+export function RestoreItem(arg1) {
+  return window['go']['main']['App']['RestoreItem'](arg1);
+}
+
+export function ReturnSerial(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ReturnSerial'](arg1, arg2, arg3);
+}
+
+export function ScanCode(arg1, arg2, arg3) {
+  return window['go']['main']['App']['ScanCode'](arg1, arg2, arg3);
+}
+
+export function SearchSerials(arg1, arg2) {
+  return window['go']['main']['App']['SearchSerials'](arg1, arg2);
+}
+
+export function SelectImportFile() {
+  return window['go']['main']['App']['SelectImportFile']();
+}
+
+export function SendPurchaseOrder(arg1) {
+  return window['go']['main']['App']['SendPurchaseOrder'](arg1);
+}
+
 export function SetCurrentVersion(arg1) {
   return window['go']['main']['App']['SetCurrentVersion'](arg1);
 }
 
-export function UpdateItem(arg1, arg2, arg3, arg4) {
-  return window['go']['main']['App']['UpdateItem'](arg1, arg2, arg3, arg4);
+export function SetScanLineQuantity(arg1, arg2) {
+  return window['go']['main']['App']['SetScanLineQuantity'](arg1, arg2);
+}
+
+export function SetSerialStatus(arg1, arg2, arg3) {
+  return window['go']['main']['App']['SetSerialStatus'](arg1, arg2, arg3);
+}
+
+export function SetupAdmin(arg1, arg2) {
+  return window['go']['main']['App']['SetupAdmin'](arg1, arg2);
+}
+
+export function StartScanSession(arg1, arg2, arg3, arg4) {
+  return window['go']['main']['App']['StartScanSession'](arg1, arg2, arg3, arg4);
+}
+
+export function StartStocktake(arg1, arg2, arg3) {
+  return window['go']['main']['App']['StartStocktake'](arg1, arg2, arg3);
+}
+
+export function TransferStock(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['TransferStock'](arg1, arg2, arg3, arg4, arg5);
+}
+
+export function UpdateIssueRequest(arg1, arg2) {
+  return window['go']['main']['App']['UpdateIssueRequest'](arg1, arg2);
+}
+
+export function UpdateItem(arg1, arg2) {
+  return window['go']['main']['App']['UpdateItem'](arg1, arg2);
+}
+
+export function UpdateLocation(arg1, arg2) {
+  return window['go']['main']['App']['UpdateLocation'](arg1, arg2);
+}
+
+export function UpdatePurchaseOrder(arg1, arg2) {
+  return window['go']['main']['App']['UpdatePurchaseOrder'](arg1, arg2);
+}
+
+export function UpdateSettings(arg1) {
+  return window['go']['main']['App']['UpdateSettings'](arg1);
+}
+
+export function UpdateSupplier(arg1, arg2) {
+  return window['go']['main']['App']['UpdateSupplier'](arg1, arg2);
+}
+
+export function UpdateUser(arg1, arg2) {
+  return window['go']['main']['App']['UpdateUser'](arg1, arg2);
+}
+
+export function WithdrawFromLot(arg1, arg2, arg3) {
+  return window['go']['main']['App']['WithdrawFromLot'](arg1, arg2, arg3);
 }
 
-export function WithdrawQuantity(arg1, arg2, arg3) {
-  return window['go']['main']['App']['WithdrawQuantity'](arg1, arg2, arg3);
+export function WithdrawQuantity(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['main']['App']['WithdrawQuantity'](arg1, arg2, arg3, arg4, arg5);
 }
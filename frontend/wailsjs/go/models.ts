@@ -1,24 +1,1507 @@
+export namespace gorm {
+	
+	export class DeletedAt {
+	    Time: time.Time;
+	    Valid: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new DeletedAt(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Time = this.convertValues(source["Time"], time.Time);
+	        this.Valid = source["Valid"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
 export namespace models {
 	
-	export class Item {
+	export class BackupInfo {
+	    name: string;
+	    kind: string;
+	    size: number;
+	    created: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new BackupInfo(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.kind = source["kind"];
+	        this.size = source["size"];
+	        this.created = this.convertValues(source["created"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ItemSerial {
+	    id: number;
+	    itemId: number;
+	    serial: string;
+	    status: string;
+	    locationId: number;
+	    issuedTo: string;
+	    comment: string;
+	    created: time.Time;
+	    updated: time.Time;
+	    item?: Item;
+	    location?: Location;
+	
+	    static createFrom(source: any = {}) {
+	        return new ItemSerial(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.itemId = source["itemId"];
+	        this.serial = source["serial"];
+	        this.status = source["status"];
+	        this.locationId = source["locationId"];
+	        this.issuedTo = source["issuedTo"];
+	        this.comment = source["comment"];
+	        this.created = this.convertValues(source["created"], time.Time);
+	        this.updated = this.convertValues(source["updated"], time.Time);
+	        this.item = this.convertValues(source["item"], Item);
+	        this.location = this.convertValues(source["location"], Location);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Location {
+	    id: number;
+	    name: string;
+	    comment: string;
+	    isDefault: boolean;
+	    created: time.Time;
+	    updated: time.Time;
+	    deleted?: gorm.DeletedAt;
+	
+	    static createFrom(source: any = {}) {
+	        return new Location(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.comment = source["comment"];
+	        this.isDefault = source["isDefault"];
+	        this.created = this.convertValues(source["created"], time.Time);
+	        this.updated = this.convertValues(source["updated"], time.Time);
+	        this.deleted = this.convertValues(source["deleted"], gorm.DeletedAt);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StockBalance {
+	    itemId: number;
+	    locationId: number;
+	    quantity: number;
+	    item?: Item;
+	    location?: Location;
+	
+	    static createFrom(source: any = {}) {
+	        return new StockBalance(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.itemId = source["itemId"];
+	        this.locationId = source["locationId"];
+	        this.quantity = source["quantity"];
+	        this.item = this.convertValues(source["item"], Item);
+	        this.location = this.convertValues(source["location"], Location);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ItemBarcode {
+	    id: number;
+	    itemId: number;
+	    code: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ItemBarcode(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.itemId = source["itemId"];
+	        this.code = source["code"];
+	    }
+	}
+	export class Item {
+	    id: number;
+	    name: string;
+	    quantity: number;
+	    precision: number;
+	    comment: string;
+	    sku: string;
+	    unit: string;
+	    category: string;
+	    location: string;
+	    minStock: number;
+	    reorderQty: number;
+	    reserved: number;
+	    available: number;
+	    costMethod: string;
+	    trackLots: boolean;
+	    trackSerials: boolean;
+	    updated: time.Time;
+	    barcodes: ItemBarcode[];
+	    balances?: StockBalance[];
+	    deleted?: gorm.DeletedAt;
+	
+	    static createFrom(source: any = {}) {
+	        return new Item(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.quantity = source["quantity"];
+	        this.precision = source["precision"];
+	        this.comment = source["comment"];
+	        this.sku = source["sku"];
+	        this.unit = source["unit"];
+	        this.category = source["category"];
+	        this.location = source["location"];
+	        this.minStock = source["minStock"];
+	        this.reorderQty = source["reorderQty"];
+	        this.reserved = source["reserved"];
+	        this.available = source["available"];
+	        this.costMethod = source["costMethod"];
+	        this.trackLots = source["trackLots"];
+	        this.trackSerials = source["trackSerials"];
+	        this.updated = this.convertValues(source["updated"], time.Time);
+	        this.barcodes = this.convertValues(source["barcodes"], ItemBarcode);
+	        this.balances = this.convertValues(source["balances"], StockBalance);
+	        this.deleted = this.convertValues(source["deleted"], gorm.DeletedAt);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class CodeLookup {
+	    code: string;
+	    found: boolean;
+	    matchedBy?: string;
+	    item?: Item;
+	    serial?: ItemSerial;
+	
+	    static createFrom(source: any = {}) {
+	        return new CodeLookup(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.code = source["code"];
+	        this.found = source["found"];
+	        this.matchedBy = source["matchedBy"];
+	        this.item = this.convertValues(source["item"], Item);
+	        this.serial = this.convertValues(source["serial"], ItemSerial);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ImportOptions {
+	    format: string;
+	    delimiter: string;
+	    encoding: string;
+	    sheet: string;
+	    columns: Record<string, string>;
+	    matchBy: string;
+	    dryRun: boolean;
+	    locationId: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportOptions(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.format = source["format"];
+	        this.delimiter = source["delimiter"];
+	        this.encoding = source["encoding"];
+	        this.sheet = source["sheet"];
+	        this.columns = source["columns"];
+	        this.matchBy = source["matchBy"];
+	        this.dryRun = source["dryRun"];
+	        this.locationId = source["locationId"];
+	    }
+	}
+	export class ImportRowError {
+	    row: number;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportRowError(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.row = source["row"];
+	        this.message = source["message"];
+	    }
+	}
+	export class ImportResult {
+	    created: number;
+	    updated: number;
+	    skipped: number;
+	    errors: ImportRowError[];
+	    dryRun: boolean;
+	    applied: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImportResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.created = source["created"];
+	        this.updated = source["updated"];
+	        this.skipped = source["skipped"];
+	        this.errors = this.convertValues(source["errors"], ImportRowError);
+	        this.dryRun = source["dryRun"];
+	        this.applied = source["applied"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class IssueRequestLine {
+	    id: number;
+	    requestId: number;
+	    itemId: number;
+	    quantity: number;
+	    item?: Item;
+	
+	    static createFrom(source: any = {}) {
+	        return new IssueRequestLine(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.requestId = source["requestId"];
+	        this.itemId = source["itemId"];
+	        this.quantity = source["quantity"];
+	        this.item = this.convertValues(source["item"], Item);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class IssueRequest {
+	    id: number;
+	    number: string;
+	    status: string;
+	    requester: string;
+	    department: string;
+	    locationId: number;
+	    comment: string;
+	    decision: string;
+	    userId?: number;
+	    userName: string;
+	    decidedBy: string;
+	    decidedAt?: time.Time;
+	    issuedBy: string;
+	    issuedAt?: time.Time;
+	    created: time.Time;
+	    updated: time.Time;
+	    location?: Location;
+	    lines?: IssueRequestLine[];
+	
+	    static createFrom(source: any = {}) {
+	        return new IssueRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.number = source["number"];
+	        this.status = source["status"];
+	        this.requester = source["requester"];
+	        this.department = source["department"];
+	        this.locationId = source["locationId"];
+	        this.comment = source["comment"];
+	        this.decision = source["decision"];
+	        this.userId = source["userId"];
+	        this.userName = source["userName"];
+	        this.decidedBy = source["decidedBy"];
+	        this.decidedAt = this.convertValues(source["decidedAt"], time.Time);
+	        this.issuedBy = source["issuedBy"];
+	        this.issuedAt = this.convertValues(source["issuedAt"], time.Time);
+	        this.created = this.convertValues(source["created"], time.Time);
+	        this.updated = this.convertValues(source["updated"], time.Time);
+	        this.location = this.convertValues(source["location"], Location);
+	        this.lines = this.convertValues(source["lines"], IssueRequestLine);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class IssueRequestLineInput {
+	    itemId: number;
+	    quantity: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new IssueRequestLineInput(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.itemId = source["itemId"];
+	        this.quantity = source["quantity"];
+	    }
+	}
+	export class IssueRequestInput {
+	    requester: string;
+	    department: string;
+	    locationId: number;
+	    comment: string;
+	    lines: IssueRequestLineInput[];
+	
+	    static createFrom(source: any = {}) {
+	        return new IssueRequestInput(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.requester = source["requester"];
+	        this.department = source["department"];
+	        this.locationId = source["locationId"];
+	        this.comment = source["comment"];
+	        this.lines = this.convertValues(source["lines"], IssueRequestLineInput);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	
+	
+	export class ItemInput {
+	    name: string;
+	    quantity: number;
+	    comment: string;
+	    sku?: string;
+	    barcodes?: string[];
+	    unit?: string;
+	    precision?: number;
+	    category?: string;
+	    location?: string;
+	    minStock?: number;
+	    reorderQty?: number;
+	    trackLots?: boolean;
+	    trackSerials?: boolean;
+	    costMethod?: string;
+	    unitCost?: number;
+	    stockLocationId?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ItemInput(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.quantity = source["quantity"];
+	        this.comment = source["comment"];
+	        this.sku = source["sku"];
+	        this.barcodes = source["barcodes"];
+	        this.unit = source["unit"];
+	        this.precision = source["precision"];
+	        this.category = source["category"];
+	        this.location = source["location"];
+	        this.minStock = source["minStock"];
+	        this.reorderQty = source["reorderQty"];
+	        this.trackLots = source["trackLots"];
+	        this.trackSerials = source["trackSerials"];
+	        this.costMethod = source["costMethod"];
+	        this.unitCost = source["unitCost"];
+	        this.stockLocationId = source["stockLocationId"];
+	    }
+	}
+	export class ItemPage {
+	    items: Item[];
+	    total: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ItemPage(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.items = this.convertValues(source["items"], Item);
+	        this.total = source["total"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ItemQuery {
+	    search: string;
+	    minQuantity?: number;
+	    maxQuantity?: number;
+	    updatedSince?: time.Time;
+	    sortBy: string;
+	    sortDesc: boolean;
+	    offset: number;
+	    limit: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ItemQuery(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.search = source["search"];
+	        this.minQuantity = source["minQuantity"];
+	        this.maxQuantity = source["maxQuantity"];
+	        this.updatedSince = this.convertValues(source["updatedSince"], time.Time);
+	        this.sortBy = source["sortBy"];
+	        this.sortDesc = source["sortDesc"];
+	        this.offset = source["offset"];
+	        this.limit = source["limit"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class LabelTemplate {
+	    format: string;
+	    symbology: string;
+	    source: string;
+	    pageWidth: number;
+	    pageHeight: number;
+	    columns: number;
+	    rows: number;
+	    labelWidth: number;
+	    labelHeight: number;
+	    marginLeft: number;
+	    marginTop: number;
+	    gapX: number;
+	    gapY: number;
+	    showName: boolean;
+	    showText: boolean;
+	    copies: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new LabelTemplate(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.format = source["format"];
+	        this.symbology = source["symbology"];
+	        this.source = source["source"];
+	        this.pageWidth = source["pageWidth"];
+	        this.pageHeight = source["pageHeight"];
+	        this.columns = source["columns"];
+	        this.rows = source["rows"];
+	        this.labelWidth = source["labelWidth"];
+	        this.labelHeight = source["labelHeight"];
+	        this.marginLeft = source["marginLeft"];
+	        this.marginTop = source["marginTop"];
+	        this.gapX = source["gapX"];
+	        this.gapY = source["gapY"];
+	        this.showName = source["showName"];
+	        this.showText = source["showText"];
+	        this.copies = source["copies"];
+	    }
+	}
+	export class LabelPreset {
+	    name: string;
+	    template: LabelTemplate;
+	
+	    static createFrom(source: any = {}) {
+	        return new LabelPreset(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.template = this.convertValues(source["template"], LabelTemplate);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class LocationInput {
+	    name: string;
+	    comment: string;
+	    isDefault: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new LocationInput(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.comment = source["comment"];
+	        this.isDefault = source["isDefault"];
+	    }
+	}
+	export class Lot {
+	    id: number;
+	    itemId: number;
+	    locationId: number;
+	    number: string;
+	    received: time.Time;
+	    expires?: time.Time;
+	    quantity: number;
+	    item?: Item;
+	    location?: Location;
+	
+	    static createFrom(source: any = {}) {
+	        return new Lot(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.itemId = source["itemId"];
+	        this.locationId = source["locationId"];
+	        this.number = source["number"];
+	        this.received = this.convertValues(source["received"], time.Time);
+	        this.expires = this.convertValues(source["expires"], time.Time);
+	        this.quantity = source["quantity"];
+	        this.item = this.convertValues(source["item"], Item);
+	        this.location = this.convertValues(source["location"], Location);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LotInput {
+	    number: string;
+	    received?: time.Time;
+	    expires?: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new LotInput(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.number = source["number"];
+	        this.received = this.convertValues(source["received"], time.Time);
+	        this.expires = this.convertValues(source["expires"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PurchaseOrderLine {
+	    id: number;
+	    orderId: number;
+	    itemId: number;
+	    ordered: number;
+	    received: number;
+	    price: number;
+	    outstanding: number;
+	    item?: Item;
+	
+	    static createFrom(source: any = {}) {
+	        return new PurchaseOrderLine(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.orderId = source["orderId"];
+	        this.itemId = source["itemId"];
+	        this.ordered = source["ordered"];
+	        this.received = source["received"];
+	        this.price = source["price"];
+	        this.outstanding = source["outstanding"];
+	        this.item = this.convertValues(source["item"], Item);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Supplier {
+	    id: number;
+	    name: string;
+	    inn: string;
+	    contact: string;
+	    phone: string;
+	    email: string;
+	    comment: string;
+	    created: time.Time;
+	    updated: time.Time;
+	    deleted?: gorm.DeletedAt;
+	
+	    static createFrom(source: any = {}) {
+	        return new Supplier(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.inn = source["inn"];
+	        this.contact = source["contact"];
+	        this.phone = source["phone"];
+	        this.email = source["email"];
+	        this.comment = source["comment"];
+	        this.created = this.convertValues(source["created"], time.Time);
+	        this.updated = this.convertValues(source["updated"], time.Time);
+	        this.deleted = this.convertValues(source["deleted"], gorm.DeletedAt);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PurchaseOrder {
+	    id: number;
+	    number: string;
+	    supplierId: number;
+	    status: string;
+	    locationId: number;
+	    comment: string;
+	    expectedAt?: time.Time;
+	    userId?: number;
+	    userName: string;
+	    created: time.Time;
+	    updated: time.Time;
+	    sentAt?: time.Time;
+	    closedAt?: time.Time;
+	    supplier?: Supplier;
+	    location?: Location;
+	    lines?: PurchaseOrderLine[];
+	
+	    static createFrom(source: any = {}) {
+	        return new PurchaseOrder(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.number = source["number"];
+	        this.supplierId = source["supplierId"];
+	        this.status = source["status"];
+	        this.locationId = source["locationId"];
+	        this.comment = source["comment"];
+	        this.expectedAt = this.convertValues(source["expectedAt"], time.Time);
+	        this.userId = source["userId"];
+	        this.userName = source["userName"];
+	        this.created = this.convertValues(source["created"], time.Time);
+	        this.updated = this.convertValues(source["updated"], time.Time);
+	        this.sentAt = this.convertValues(source["sentAt"], time.Time);
+	        this.closedAt = this.convertValues(source["closedAt"], time.Time);
+	        this.supplier = this.convertValues(source["supplier"], Supplier);
+	        this.location = this.convertValues(source["location"], Location);
+	        this.lines = this.convertValues(source["lines"], PurchaseOrderLine);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PurchaseOrderLineInput {
+	    itemId: number;
+	    ordered: number;
+	    price: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PurchaseOrderLineInput(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.itemId = source["itemId"];
+	        this.ordered = source["ordered"];
+	        this.price = source["price"];
+	    }
+	}
+	export class PurchaseOrderInput {
+	    supplierId: number;
+	    locationId: number;
+	    comment: string;
+	    expectedAt?: time.Time;
+	    lines: PurchaseOrderLineInput[];
+	
+	    static createFrom(source: any = {}) {
+	        return new PurchaseOrderInput(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.supplierId = source["supplierId"];
+	        this.locationId = source["locationId"];
+	        this.comment = source["comment"];
+	        this.expectedAt = this.convertValues(source["expectedAt"], time.Time);
+	        this.lines = this.convertValues(source["lines"], PurchaseOrderLineInput);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class ReceiptLine {
+	    lineId: number;
+	    quantity: number;
+	    lot?: LotInput;
+	    serials?: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ReceiptLine(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.lineId = source["lineId"];
+	        this.quantity = source["quantity"];
+	        this.lot = this.convertValues(source["lot"], LotInput);
+	        this.serials = source["serials"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Reservation {
+	    id: number;
+	    itemId: number;
+	    quantity: number;
+	    holder: string;
+	    comment: string;
+	    expiresAt?: time.Time;
+	    requestId?: number;
+	    locationId?: number;
+	    userId?: number;
+	    userName: string;
+	    created: time.Time;
+	    updated: time.Time;
+	    item?: Item;
+	
+	    static createFrom(source: any = {}) {
+	        return new Reservation(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.itemId = source["itemId"];
+	        this.quantity = source["quantity"];
+	        this.holder = source["holder"];
+	        this.comment = source["comment"];
+	        this.expiresAt = this.convertValues(source["expiresAt"], time.Time);
+	        this.requestId = source["requestId"];
+	        this.locationId = source["locationId"];
+	        this.userId = source["userId"];
+	        this.userName = source["userName"];
+	        this.created = this.convertValues(source["created"], time.Time);
+	        this.updated = this.convertValues(source["updated"], time.Time);
+	        this.item = this.convertValues(source["item"], Item);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ReservationInput {
+	    itemId: number;
+	    locationId: number;
+	    quantity: number;
+	    holder: string;
+	    comment: string;
+	    expiresAt?: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new ReservationInput(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.itemId = source["itemId"];
+	        this.locationId = source["locationId"];
+	        this.quantity = source["quantity"];
+	        this.holder = source["holder"];
+	        this.comment = source["comment"];
+	        this.expiresAt = this.convertValues(source["expiresAt"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ScanLine {
+	    id: number;
+	    sessionId: number;
+	    itemId: number;
+	    serialId?: number;
+	    code: string;
+	    quantity: number;
+	    created: time.Time;
+	    updated: time.Time;
+	    item?: Item;
+	    serial?: ItemSerial;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScanLine(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.sessionId = source["sessionId"];
+	        this.itemId = source["itemId"];
+	        this.serialId = source["serialId"];
+	        this.code = source["code"];
+	        this.quantity = source["quantity"];
+	        this.created = this.convertValues(source["created"], time.Time);
+	        this.updated = this.convertValues(source["updated"], time.Time);
+	        this.item = this.convertValues(source["item"], Item);
+	        this.serial = this.convertValues(source["serial"], ItemSerial);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ScanResult {
+	    lookup: CodeLookup;
+	    line?: ScanLine;
+	
+	    static createFrom(source: any = {}) {
+	        return new ScanResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.lookup = this.convertValues(source["lookup"], CodeLookup);
+	        this.line = this.convertValues(source["line"], ScanLine);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ScanSession {
 	    id: number;
-	    name: string;
-	    quantity: number;
+	    kind: string;
+	    status: string;
+	    locationId: number;
 	    comment: string;
-	    // Go type: time
-	    updated: any;
+	    reference: string;
+	    userId?: number;
+	    userName: string;
+	    created: time.Time;
+	    updated: time.Time;
+	    closed?: time.Time;
+	    location?: Location;
+	    lines: ScanLine[];
 	
 	    static createFrom(source: any = {}) {
-	        return new Item(source);
+	        return new ScanSession(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
-	        this.name = source["name"];
-	        this.quantity = source["quantity"];
+	        this.kind = source["kind"];
+	        this.status = source["status"];
+	        this.locationId = source["locationId"];
+	        this.comment = source["comment"];
+	        this.reference = source["reference"];
+	        this.userId = source["userId"];
+	        this.userName = source["userName"];
+	        this.created = this.convertValues(source["created"], time.Time);
+	        this.updated = this.convertValues(source["updated"], time.Time);
+	        this.closed = this.convertValues(source["closed"], time.Time);
+	        this.location = this.convertValues(source["location"], Location);
+	        this.lines = this.convertValues(source["lines"], ScanLine);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Settings {
+	    version: number;
+	    updateRepoOwner: string;
+	    updateRepoName: string;
+	    updateCheckIntervalHours: number;
+	    updateInitialDelaySeconds: number;
+	    databaseFile: string;
+	    backupIntervalHours: number;
+	    backupKeepDaily: number;
+	    backupKeepWeekly: number;
+	    expiryWarningDays: number;
+	    windowWidth: number;
+	    windowHeight: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Settings(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.version = source["version"];
+	        this.updateRepoOwner = source["updateRepoOwner"];
+	        this.updateRepoName = source["updateRepoName"];
+	        this.updateCheckIntervalHours = source["updateCheckIntervalHours"];
+	        this.updateInitialDelaySeconds = source["updateInitialDelaySeconds"];
+	        this.databaseFile = source["databaseFile"];
+	        this.backupIntervalHours = source["backupIntervalHours"];
+	        this.backupKeepDaily = source["backupKeepDaily"];
+	        this.backupKeepWeekly = source["backupKeepWeekly"];
+	        this.expiryWarningDays = source["expiryWarningDays"];
+	        this.windowWidth = source["windowWidth"];
+	        this.windowHeight = source["windowHeight"];
+	    }
+	}
+	
+	export class StockMovement {
+	    id: number;
+	    itemId: number;
+	    item?: Item;
+	    delta: number;
+	    reason: string;
+	    comment: string;
+	    reference: string;
+	    balance: number;
+	    locationId?: number;
+	    lotId?: number;
+	    serialId?: number;
+	    userId?: number;
+	    userName: string;
+	    unitCost: number;
+	    cost: number;
+	    created: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new StockMovement(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.itemId = source["itemId"];
+	        this.item = this.convertValues(source["item"], Item);
+	        this.delta = source["delta"];
+	        this.reason = source["reason"];
+	        this.comment = source["comment"];
+	        this.reference = source["reference"];
+	        this.balance = source["balance"];
+	        this.locationId = source["locationId"];
+	        this.lotId = source["lotId"];
+	        this.serialId = source["serialId"];
+	        this.userId = source["userId"];
+	        this.userName = source["userName"];
+	        this.unitCost = source["unitCost"];
+	        this.cost = source["cost"];
+	        this.created = this.convertValues(source["created"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class StocktakeLine {
+	    id: number;
+	    stocktakeId: number;
+	    itemId: number;
+	    expected: number;
+	    counted?: number;
+	    variance: number;
+	    created: time.Time;
+	    updated: time.Time;
+	    item?: Item;
+	
+	    static createFrom(source: any = {}) {
+	        return new StocktakeLine(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.stocktakeId = source["stocktakeId"];
+	        this.itemId = source["itemId"];
+	        this.expected = source["expected"];
+	        this.counted = source["counted"];
+	        this.variance = source["variance"];
+	        this.created = this.convertValues(source["created"], time.Time);
+	        this.updated = this.convertValues(source["updated"], time.Time);
+	        this.item = this.convertValues(source["item"], Item);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Stocktake {
+	    id: number;
+	    status: string;
+	    locationId: number;
+	    category: string;
+	    comment: string;
+	    reference: string;
+	    userId?: number;
+	    userName: string;
+	    created: time.Time;
+	    updated: time.Time;
+	    closed?: time.Time;
+	    location?: Location;
+	    lines?: StocktakeLine[];
+	
+	    static createFrom(source: any = {}) {
+	        return new Stocktake(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.status = source["status"];
+	        this.locationId = source["locationId"];
+	        this.category = source["category"];
 	        this.comment = source["comment"];
-	        this.updated = this.convertValues(source["updated"], null);
+	        this.reference = source["reference"];
+	        this.userId = source["userId"];
+	        this.userName = source["userName"];
+	        this.created = this.convertValues(source["created"], time.Time);
+	        this.updated = this.convertValues(source["updated"], time.Time);
+	        this.closed = this.convertValues(source["closed"], time.Time);
+	        this.location = this.convertValues(source["location"], Location);
+	        this.lines = this.convertValues(source["lines"], StocktakeLine);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -39,6 +1522,30 @@ export namespace models {
 		    return a;
 		}
 	}
+	
+	
+	export class SupplierInput {
+	    name: string;
+	    inn: string;
+	    contact: string;
+	    phone: string;
+	    email: string;
+	    comment: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SupplierInput(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.inn = source["inn"];
+	        this.contact = source["contact"];
+	        this.phone = source["phone"];
+	        this.email = source["email"];
+	        this.comment = source["comment"];
+	    }
+	}
 	export class UpdateStatus {
 	    currentVersion: string;
 	    latestVersion: string;
@@ -59,6 +1566,165 @@ export namespace models {
 	        this.error = source["error"];
 	    }
 	}
+	export class User {
+	    id: number;
+	    username: string;
+	    displayName: string;
+	    role: string;
+	    disabled: boolean;
+	    lastLogin?: time.Time;
+	    created: time.Time;
+	    updated: time.Time;
+	
+	    static createFrom(source: any = {}) {
+	        return new User(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.username = source["username"];
+	        this.displayName = source["displayName"];
+	        this.role = source["role"];
+	        this.disabled = source["disabled"];
+	        this.lastLogin = this.convertValues(source["lastLogin"], time.Time);
+	        this.created = this.convertValues(source["created"], time.Time);
+	        this.updated = this.convertValues(source["updated"], time.Time);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class UserInput {
+	    username: string;
+	    displayName: string;
+	    role: string;
+	    password: string;
+	    disabled: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new UserInput(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.username = source["username"];
+	        this.displayName = source["displayName"];
+	        this.role = source["role"];
+	        this.password = source["password"];
+	        this.disabled = source["disabled"];
+	    }
+	}
+	export class ValuationCategory {
+	    category: string;
+	    items: number;
+	    value: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ValuationCategory(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.category = source["category"];
+	        this.items = source["items"];
+	        this.value = source["value"];
+	    }
+	}
+	export class ValuationLine {
+	    itemId: number;
+	    name: string;
+	    sku: string;
+	    category: string;
+	    unit: string;
+	    quantity: number;
+	    value: number;
+	    unitCost: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ValuationLine(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.itemId = source["itemId"];
+	        this.name = source["name"];
+	        this.sku = source["sku"];
+	        this.category = source["category"];
+	        this.unit = source["unit"];
+	        this.quantity = source["quantity"];
+	        this.value = source["value"];
+	        this.unitCost = source["unitCost"];
+	    }
+	}
+	export class ValuationReport {
+	    asOf: time.Time;
+	    lines: ValuationLine[];
+	    categories: ValuationCategory[];
+	    total: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ValuationReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.asOf = this.convertValues(source["asOf"], time.Time);
+	        this.lines = this.convertValues(source["lines"], ValuationLine);
+	        this.categories = this.convertValues(source["categories"], ValuationCategory);
+	        this.total = source["total"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+
+}
+
+export namespace time {
+	
+	export class Time {
+	
+	
+	    static createFrom(source: any = {}) {
+	        return new Time(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	
+	    }
+	}
 
 }
 
@@ -3,6 +3,8 @@ package main
 import (
 	"embed"
 
+	"goods_wails_app/models"
+
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/options"
 	"github.com/wailsapp/wails/v2/pkg/options/assetserver"
@@ -14,19 +16,21 @@ var assets embed.FS
 func main() {
 	// Create an instance of the app structure
 	app := NewApp()
+	settings := app.currentSettings()
 
 	// Create application with options
 	err := wails.Run(&options.App{
 		Title:     "Система управления складом",
-		Width:     1024,
-		Height:    768,
-		MinWidth:  1024,
-		MinHeight: 768,
+		Width:     settings.WindowWidth,
+		Height:    settings.WindowHeight,
+		MinWidth:  models.MinWindowWidth,
+		MinHeight: models.MinWindowHeight,
 		AssetServer: &assetserver.Options{
 			Assets: assets,
 		},
 		BackgroundColour:         &options.RGBA{R: 27, G: 38, B: 54, A: 1},
 		OnStartup:                app.startup,
+		OnDomReady:               app.domReady,
 		Fullscreen:               false,
 		DisableResize:            false,
 		AlwaysOnTop:              false,
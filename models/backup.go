@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Backup kinds, recorded in the backup file name.
+const (
+	BackupStartup    = "startup"
+	BackupScheduled  = "scheduled"
+	BackupPreUpdate  = "pre-update"
+	BackupPreRestore = "pre-restore"
+	BackupManual     = "manual"
+	// BackupPreMigration is written by Migrate before it changes the schema.
+	BackupPreMigration = "pre-migration"
+)
+
+// BackupInfo describes one database snapshot in the backup directory.
+type BackupInfo struct {
+	Name      string    `json:"name"` // file name, used to restore
+	Kind      string    `json:"kind"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created"`
+}
@@ -0,0 +1,110 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// parseFixed parses a decimal string such as "12", "-1.25" or "3,5" into an
+// integer count of 10^-decimals. what names the value in error messages.
+func parseFixed(s string, what string, decimals int) (int64, error) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), ",", ".")
+	if s == "" {
+		return 0, fmt.Errorf("empty %s", what)
+	}
+	neg := false
+	switch s[0] {
+	case '-':
+		neg = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" {
+		return 0, fmt.Errorf("invalid %s %q", what, s)
+	}
+	if len(frac) > decimals {
+		if strings.TrimRight(frac[decimals:], "0") != "" {
+			return 0, fmt.Errorf("%s %q has more than %d decimals", what, s, decimals)
+		}
+		frac = frac[:decimals]
+	}
+	frac += strings.Repeat("0", decimals-len(frac))
+	if whole == "" {
+		whole = "0"
+	}
+	w, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || w < 0 {
+		return 0, fmt.Errorf("invalid %s %q", what, s)
+	}
+	var f int64
+	if frac != "" {
+		f, err = strconv.ParseInt(frac, 10, 64)
+		if err != nil || f < 0 {
+			return 0, fmt.Errorf("invalid %s %q", what, s)
+		}
+	}
+	scale := int64(math.Pow10(decimals))
+	if w > (math.MaxInt64-f)/scale {
+		return 0, fmt.Errorf("%s %q out of range", what, s)
+	}
+	v := w*scale + f
+	if neg {
+		v = -v
+	}
+	return v, nil
+}
+
+// formatFixed formats an integer count of 10^-decimals as a decimal string.
+// With trim, trailing fractional zeros are dropped ("1.25", "3").
+func formatFixed(v int64, decimals int, trim bool) string {
+	sign := ""
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	scale := int64(math.Pow10(decimals))
+	whole := v / scale
+	frac := v % scale
+	if decimals == 0 || trim && frac == 0 {
+		return sign + strconv.FormatInt(whole, 10)
+	}
+	f := fmt.Sprintf("%0*d", decimals, frac)
+	if trim {
+		f = strings.TrimRight(f, "0")
+	}
+	return sign + strconv.FormatInt(whole, 10) + "." + f
+}
+
+// unmarshalFixed decodes a JSON number or numeric string into an integer
+// count of 10^-decimals. Numbers coming from JavaScript may carry float
+// noise (0.30000000000000004), so they are rounded; strings are parsed
+// strictly.
+func unmarshalFixed(b []byte, what string, decimals int) (int64, error) {
+	s := strings.TrimSpace(string(b))
+	if s == "null" {
+		return 0, nil
+	}
+	if strings.HasPrefix(s, `"`) {
+		unquoted, err := strconv.Unquote(s)
+		if err != nil {
+			return 0, err
+		}
+		return parseFixed(unquoted, what, decimals)
+	}
+	if v, err := parseFixed(s, what, decimals); err == nil && !strings.ContainsAny(s, "eE") {
+		return v, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %s", what, s)
+	}
+	scaled := math.Round(f * math.Pow10(decimals))
+	if math.Abs(scaled) > math.MaxInt64/2 {
+		return 0, fmt.Errorf("%s %s out of range", what, s)
+	}
+	return int64(scaled), nil
+}
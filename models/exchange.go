@@ -0,0 +1,71 @@
+package models
+
+// File formats of ImportItems (csv, xlsx), ExportItems (csv, xlsx, pdf)
+// and GenerateLabels (pdf, png).
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+	FormatPDF  = "pdf" // printable stock sheet or label sheet, export only
+	FormatPNG  = "png" // single labels only
+)
+
+// Text encodings accepted by ImportOptions.Encoding.
+const (
+	EncodingAuto        = "auto" // UTF-8 unless the file is not valid UTF-8, then Windows-1251
+	EncodingUTF8        = "utf-8"
+	EncodingWindows1251 = "windows-1251"
+)
+
+// Import match modes for ImportOptions.MatchBy.
+const (
+	MatchByName = "name"
+	MatchBySKU  = "sku"
+)
+
+// Item fields that import columns can be mapped to (keys of ImportOptions.Columns).
+const (
+	FieldName       = "name"
+	FieldQuantity   = "quantity"
+	FieldComment    = "comment"
+	FieldSKU        = "sku"
+	FieldBarcodes   = "barcodes"
+	FieldUnit       = "unit"
+	FieldCategory   = "category"
+	FieldLocation   = "location"
+	FieldMinStock   = "minStock"
+	FieldReorderQty = "reorderQty"
+)
+
+// ImportOptions controls how ImportItems reads and applies a catalog file.
+type ImportOptions struct {
+	Format    string `json:"format"`    // csv or xlsx; taken from the file extension when empty
+	Delimiter string `json:"delimiter"` // CSV only; detected from the header line when empty
+	Encoding  string `json:"encoding"`  // CSV only; auto (default), utf-8 or windows-1251
+	Sheet     string `json:"sheet"`     // XLSX only; first sheet when empty
+	// Columns maps item fields (FieldName, FieldQuantity, ...) to header
+	// titles in the file. Unmapped fields are matched against common
+	// Russian and English titles ("Наименование", "Артикул", "Qty", ...).
+	Columns map[string]string `json:"columns"`
+	MatchBy string            `json:"matchBy"` // name (default) or sku: how rows find existing items
+	DryRun  bool              `json:"dryRun"`  // validate and count without writing anything
+	// LocationID is where quantity changes are booked, as ItemInput.StockLocationID.
+	LocationID uint `json:"locationId"`
+}
+
+// ImportRowError describes why a single input row was rejected.
+// Row is the 1-based line / row number in the file, header included.
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Message string `json:"message"`
+}
+
+// ImportResult summarises an import. When Errors is non-empty nothing was
+// written, exactly as for a dry run.
+type ImportResult struct {
+	Created int              `json:"created"`
+	Updated int              `json:"updated"`
+	Skipped int              `json:"skipped"` // blank rows
+	Errors  []ImportRowError `json:"errors"`
+	DryRun  bool             `json:"dryRun"`
+	Applied bool             `json:"applied"` // changes were committed
+}
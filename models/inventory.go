@@ -2,16 +2,139 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 // Note: Keep field names exported for GORM and Wails bindings.
 type Item struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	Name      string    `gorm:"not null;index" json:"name"`
-	Quantity  int       `gorm:"not null;default:0" json:"quantity"`
-	Comment   string    `gorm:"type:text" json:"comment"`
-	UpdatedAt time.Time `json:"updated"`
-	CreatedAt time.Time `json:"-"`
+	ID        uint     `gorm:"primaryKey" json:"id"`
+	Name      string   `gorm:"not null;index" json:"name"`
+	Quantity  Quantity `gorm:"not null;default:0;index" json:"quantity"`
+	Precision int      `gorm:"not null;default:0" json:"precision"` // allowed decimals of Quantity, 0..QuantityDecimals
+	Comment   string   `gorm:"type:text" json:"comment"`
+	SKU       string   `gorm:"not null;default:'';uniqueIndex" json:"sku"`
+	Unit      string   `gorm:"not null;default:'шт'" json:"unit"` // unit of measure, e.g. "шт", "кг"
+	Category  string   `gorm:"not null;default:'';index" json:"category"`
+	Location  string   `gorm:"not null;default:''" json:"location"` // shelf / bin where the item is kept
+	// MinStock is the reorder point; 0 disables low-stock alerts.
+	MinStock   Quantity `gorm:"not null;default:0" json:"minStock"`
+	ReorderQty Quantity `gorm:"not null;default:0" json:"reorderQty"` // suggested amount to order
+	// Reserved is the sum of the item's reservations: stock that is still
+	// here but promised, e.g. to approved issue requests.
+	Reserved Quantity `gorm:"not null;default:0" json:"reserved"`
+	// Available is Quantity - Reserved, filled in when the item is loaded.
+	Available Quantity `gorm:"-" json:"available"`
+	// CostMethod is how withdrawals are valued: CostAverage or CostFIFO.
+	CostMethod string `gorm:"not null;default:'average'" json:"costMethod"`
+	// TrackLots keeps the stock of the item in lots with expiry dates.
+	TrackLots bool `gorm:"not null;default:false" json:"trackLots"`
+	// TrackSerials derives Quantity from the item's ItemSerial records:
+	// one unit per serial in stock. Not combinable with TrackLots.
+	TrackSerials bool          `gorm:"not null;default:false" json:"trackSerials"`
+	UpdatedAt    time.Time     `gorm:"index" json:"updated"`
+	CreatedAt    time.Time     `json:"-"`
+	Barcodes     []ItemBarcode `gorm:"foreignKey:ItemID" json:"barcodes"`
+	// Balances splits Quantity by location; loaded by the consolidated listings.
+	Balances []StockBalance `gorm:"foreignKey:ItemID" json:"balances,omitempty"`
+	// DeletedAt marks a soft-deleted item; such items are hidden from
+	// regular queries but keep their movement history.
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted,omitempty"`
+}
+
+// AfterFind fills in Available for every loaded item.
+func (i *Item) AfterFind(*gorm.DB) error {
+	i.Available = i.Quantity - i.Reserved
+	return nil
+}
+
+// AfterSave keeps Available in step after the item is created or saved.
+func (i *Item) AfterSave(tx *gorm.DB) error {
+	return i.AfterFind(tx)
+}
+
+// IsLowStock reports whether the item has a minimum stock set and is at or below it.
+func (i *Item) IsLowStock() bool {
+	return IsLowStock(i.Quantity, i.MinStock)
+}
+
+// IsLowStock reports whether quantity is at or below a non-zero minimum.
+func IsLowStock(quantity, minStock Quantity) bool {
+	return minStock > 0 && quantity <= minStock
+}
+
+// ItemBarcode is one of the barcodes printed on an item's packaging.
+// Codes are unique across the whole catalog.
+type ItemBarcode struct {
+	ID     uint   `gorm:"primaryKey" json:"id"`
+	ItemID uint   `gorm:"not null;index" json:"itemId"`
+	Code   string `gorm:"not null;uniqueIndex" json:"code"`
+}
+
+// DefaultUnit is the unit of measure assigned when none is given.
+const DefaultUnit = "шт"
+
+// ItemInput carries the editable fields of CreateItem and UpdateItem.
+// Catalog fields are optional: nil keeps the stored value on update
+// (or the default on create), so older callers that only send name,
+// quantity and comment do not wipe them.
+type ItemInput struct {
+	Name     string    `json:"name"`
+	Quantity Quantity  `json:"quantity"`
+	Comment  string    `json:"comment"`
+	SKU      *string   `json:"sku,omitempty"` // generated from the id when empty
+	Barcodes *[]string `json:"barcodes,omitempty"`
+	Unit     *string   `json:"unit,omitempty"`
+	// Precision defaults to UnitPrecision(unit) when the unit is set and this is nil.
+	Precision  *int      `json:"precision,omitempty"`
+	Category   *string   `json:"category,omitempty"`
+	Location   *string   `json:"location,omitempty"`
+	MinStock   *Quantity `json:"minStock,omitempty"`
+	ReorderQty *Quantity `json:"reorderQty,omitempty"`
+	// TrackLots switches lot tracking. Turning it on puts the current stock
+	// into lots without number or expiry; turning it off empties the lots.
+	TrackLots *bool `json:"trackLots,omitempty"`
+	// TrackSerials switches serial tracking; it can only be turned on here
+	// while the item has no stock, and off while none of its serials are in
+	// stock. Stock on hand gets its serial numbers through
+	// EnableSerialTracking.
+	TrackSerials *bool `json:"trackSerials,omitempty"`
+	// CostMethod switches between CostAverage and CostFIFO valuation.
+	CostMethod *string `json:"costMethod,omitempty"`
+	// UnitCost values the stock a create or quantity increase adds; nil
+	// uses the item's current average cost, zero books it as free.
+	UnitCost *Money `json:"unitCost,omitempty"`
+	// StockLocationID is the location a change of Quantity is booked at.
+	// 0 means the only location holding the item's stock (the default one
+	// while there is none); it is required once stock is spread out.
+	StockLocationID uint `json:"stockLocationId,omitempty"`
+}
+
+// Sort fields accepted by ItemQuery.SortBy.
+const (
+	SortByName     = "name"
+	SortByQuantity = "quantity"
+	SortByUpdated  = "updated"
+)
+
+// ItemQuery describes server-side filtering, sorting and paging of items.
+// Zero values disable the corresponding filter.
+type ItemQuery struct {
+	Search       string     `json:"search"`       // substring of name, comment, SKU or a serial number, case-insensitive
+	MinQuantity  *Quantity  `json:"minQuantity"`  // inclusive
+	MaxQuantity  *Quantity  `json:"maxQuantity"`  // inclusive
+	UpdatedSince *time.Time `json:"updatedSince"` // inclusive
+	SortBy       string     `json:"sortBy"`       // name (default), quantity or updated
+	SortDesc     bool       `json:"sortDesc"`
+	Offset       int        `json:"offset"`
+	Limit        int        `json:"limit"` // <= 0 means no limit
+}
+
+// ItemPage is a single page of QueryItems results.
+// Total is the number of items matching the filter regardless of paging.
+type ItemPage struct {
+	Items []Item `json:"items"`
+	Total int64  `json:"total"`
 }
 
 // UpdateStatus represents application update state exposed to the frontend.
@@ -0,0 +1,83 @@
+package models
+
+// Barcode symbologies of LabelTemplate.Symbology.
+const (
+	SymbologyCode128 = "code128"
+	SymbologyEAN13   = "ean13" // needs a 12 or 13 digit value
+	SymbologyQR      = "qr"
+)
+
+// What a label code encodes, for LabelTemplate.Source.
+const (
+	LabelSourceSKU     = "sku"     // the SKU, or the item ID when there is none
+	LabelSourceBarcode = "barcode" // the first registered barcode, or the SKU
+	LabelSourceID      = "id"      // the item ID
+)
+
+// LabelTemplate describes the label layout used by GenerateLabels. All
+// lengths are in millimetres. A PDF places Columns x Rows labels per page;
+// a PNG file holds a single label, one file per label.
+type LabelTemplate struct {
+	Format      string  `json:"format"`      // pdf (default) or png
+	Symbology   string  `json:"symbology"`   // code128 (default), ean13 or qr
+	Source      string  `json:"source"`      // sku (default), barcode or id
+	PageWidth   float64 `json:"pageWidth"`   // PDF page width; A4 when zero
+	PageHeight  float64 `json:"pageHeight"`  // PDF page height; A4 when zero
+	Columns     int     `json:"columns"`     // labels across the page
+	Rows        int     `json:"rows"`        // labels down the page
+	LabelWidth  float64 `json:"labelWidth"`  // zero splits the page width evenly
+	LabelHeight float64 `json:"labelHeight"` // zero splits the page height evenly
+	MarginLeft  float64 `json:"marginLeft"`  // zero centres the grid across
+	MarginTop   float64 `json:"marginTop"`   // zero centres the grid down
+	GapX        float64 `json:"gapX"`        // space between columns
+	GapY        float64 `json:"gapY"`        // space between rows
+	ShowName    bool    `json:"showName"`    // item name above the code
+	ShowText    bool    `json:"showText"`    // encoded value under the code
+	Copies      int     `json:"copies"`      // labels per item, 1 when zero
+}
+
+// LabelPreset is a named LabelTemplate offered in the print dialog.
+type LabelPreset struct {
+	Name     string        `json:"name"`
+	Template LabelTemplate `json:"template"`
+}
+
+// A4 page size in millimetres.
+const (
+	A4Width  = 210.0
+	A4Height = 297.0
+)
+
+// LabelPresets returns the common label sheets and a single thermal label.
+func LabelPresets() []LabelPreset {
+	sheet := func(columns, rows int) LabelTemplate {
+		return LabelTemplate{
+			Format:    FormatPDF,
+			Symbology: SymbologyCode128,
+			Source:    LabelSourceSKU,
+			Columns:   columns,
+			Rows:      rows,
+			ShowName:  true,
+			ShowText:  true,
+		}
+	}
+	return []LabelPreset{
+		{Name: "A4 3x8 (70x37 мм)", Template: sheet(3, 8)},
+		{Name: "A4 2x7 (105x42 мм)", Template: sheet(2, 7)},
+		{Name: "A4 4x10 (52x29 мм)", Template: sheet(4, 10)},
+		{Name: "A4 5x13 (38x21 мм)", Template: sheet(5, 13)},
+		{Name: "Термоэтикетка 58x40 мм", Template: LabelTemplate{
+			Format:      FormatPDF,
+			Symbology:   SymbologyCode128,
+			Source:      LabelSourceSKU,
+			PageWidth:   58,
+			PageHeight:  40,
+			Columns:     1,
+			Rows:        1,
+			LabelWidth:  58,
+			LabelHeight: 40,
+			ShowName:    true,
+			ShowText:    true,
+		}},
+	}
+}
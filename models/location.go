@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DefaultLocationName is the storeroom created for stock that existed
+// before locations were introduced.
+const DefaultLocationName = "Основной склад"
+
+// Location is a warehouse or storeroom holding its own stock. It is not the
+// shelf/bin label in Item.Location, which describes where an item sits
+// inside a location.
+type Location struct {
+	ID      uint   `gorm:"primaryKey" json:"id"`
+	Name    string `gorm:"not null;uniqueIndex:idx_locations_name,where:deleted_at IS NULL" json:"name"`
+	Comment string `gorm:"not null;default:''" json:"comment"`
+	// IsDefault marks the location used when an operation names none, such
+	// as opening balances, catalog imports and quantity adjustments.
+	IsDefault bool           `gorm:"not null;default:false" json:"isDefault"`
+	CreatedAt time.Time      `json:"created"`
+	UpdatedAt time.Time      `json:"updated"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted,omitempty"`
+}
+
+// LocationInput carries the editable fields of CreateLocation and UpdateLocation.
+type LocationInput struct {
+	Name      string `json:"name"`
+	Comment   string `json:"comment"`
+	IsDefault bool   `json:"isDefault"`
+}
+
+// StockBalance is the quantity of one item held at one location. The
+// balances of an item always add up to Item.Quantity.
+type StockBalance struct {
+	ItemID     uint      `gorm:"primaryKey;autoIncrement:false" json:"itemId"`
+	LocationID uint      `gorm:"primaryKey;autoIncrement:false;index" json:"locationId"`
+	Quantity   Quantity  `gorm:"not null;default:0" json:"quantity"`
+	Item       *Item     `gorm:"foreignKey:ItemID" json:"item,omitempty"`
+	Location   *Location `gorm:"foreignKey:LocationID" json:"location,omitempty"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Lot is a batch of a lot-tracked item received together and held at one
+// location. The lots of an item at a location add up to its StockBalance
+// there. Lots are consumed first-expiry-first-out unless one is chosen;
+// expired lots are only issued when chosen.
+type Lot struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	ItemID     uint       `gorm:"not null;index" json:"itemId"`
+	LocationID uint       `gorm:"not null;index" json:"locationId"`
+	Number     string     `gorm:"not null;default:''" json:"number"` // manufacturer batch number; empty if unknown
+	ReceivedAt time.Time  `gorm:"not null" json:"received"`
+	ExpiresAt  *time.Time `gorm:"index" json:"expires,omitempty"` // nil for goods that do not expire
+	Quantity   Quantity   `gorm:"not null;default:0" json:"quantity"`
+	CreatedAt  time.Time  `json:"-"`
+	Item       *Item      `gorm:"foreignKey:ItemID" json:"item,omitempty"`
+	Location   *Location  `gorm:"foreignKey:LocationID" json:"location,omitempty"`
+}
+
+// LotInput describes the lot goods are received into. A receipt with the
+// same number and expiry as an existing lot at the location adds to it.
+type LotInput struct {
+	Number     string     `json:"number"`
+	ReceivedAt *time.Time `json:"received,omitempty"` // defaults to now
+	ExpiresAt  *time.Time `json:"expires,omitempty"`  // the time of day is dropped
+}
+
+// IsExpired reports whether the lot's expiry date has passed at now.
+func (l *Lot) IsExpired(now time.Time) bool {
+	return l.ExpiresAt != nil && !l.ExpiresAt.After(now)
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"math/big"
+	"strings"
+)
+
+// MoneyDecimals is the number of fractional digits of a Money amount (kopecks).
+const MoneyDecimals = 2
+
+// Money is an amount in roubles stored as an integer number of kopecks, so
+// sums are exact. In JSON it is a plain decimal number (12.5).
+type Money int64
+
+// ParseMoney parses a decimal string such as "12", "1 250,50" or "0.99".
+func ParseMoney(s string) (Money, error) {
+	v, err := parseFixed(strings.ReplaceAll(s, " ", ""), "amount", MoneyDecimals)
+	return Money(v), err
+}
+
+// String formats m with two decimals ("12.50").
+func (m Money) String() string {
+	return formatFixed(int64(m), MoneyDecimals, false)
+}
+
+// Float returns m as a float64, for display and reports only.
+func (m Money) Float() float64 {
+	return float64(m) / 100
+}
+
+// MarshalJSON encodes m as a JSON number.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or numeric string, rounding float
+// noise from JavaScript to whole kopecks.
+func (m *Money) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalFixed(b, "amount", MoneyDecimals)
+	if err != nil {
+		return err
+	}
+	*m = Money(v)
+	return nil
+}
+
+// Amount returns the cost of qty at unit price m, rounded half away from
+// zero to whole kopecks.
+func (m Money) Amount(qty Quantity) Money {
+	p := new(big.Int).Mul(big.NewInt(int64(m)), big.NewInt(int64(qty)))
+	return Money(divRound(p, big.NewInt(QuantityScale)).Int64())
+}
+
+// PerUnit returns the unit price of qty units costing m in total, rounded
+// to whole kopecks. A zero qty yields zero.
+func (m Money) PerUnit(qty Quantity) Money {
+	if qty == 0 {
+		return 0
+	}
+	p := new(big.Int).Mul(big.NewInt(int64(m)), big.NewInt(QuantityScale))
+	return Money(divRound(p, big.NewInt(int64(qty))).Int64())
+}
+
+// Share returns the part of m that part of whole units carry, rounded to
+// whole kopecks. A zero whole yields zero.
+func (m Money) Share(part, whole Quantity) Money {
+	if whole == 0 {
+		return 0
+	}
+	p := new(big.Int).Mul(big.NewInt(int64(m)), big.NewInt(int64(part)))
+	return Money(divRound(p, big.NewInt(int64(whole))).Int64())
+}
+
+// divRound divides a by b rounding half away from zero.
+func divRound(a, b *big.Int) *big.Int {
+	q, r := new(big.Int).QuoRem(a, b, new(big.Int))
+	if new(big.Int).Abs(new(big.Int).Mul(r, big.NewInt(2))).Cmp(new(big.Int).Abs(b)) >= 0 {
+		if (a.Sign() < 0) != (b.Sign() < 0) {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return q
+}
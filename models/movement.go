@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// Movement reasons stored in StockMovement.Reason.
+const (
+	MovementCreate   = "create"
+	MovementAdjust   = "adjust"
+	MovementWithdraw = "withdraw"
+	MovementReceive  = "receive"
+	MovementDelete   = "delete"  // zero delta, audit only
+	MovementRestore  = "restore" // zero delta, audit only
+	MovementImport   = "import"
+	MovementTransfer = "transfer" // one entry out of the source, one into the target location
+	// Serial status changes; issue, repair and write-off take the unit out
+	// of stock, return puts it back.
+	MovementIssue    = "issue"
+	MovementReturn   = "return"
+	MovementRepair   = "repair"
+	MovementWriteOff = "write-off"
+)
+
+// StockMovement is a single ledger entry written for every quantity change.
+// Delta is signed; Balance is the item quantity right after the change.
+type StockMovement struct {
+	ID        uint     `gorm:"primaryKey" json:"id"`
+	ItemID    uint     `gorm:"not null;index" json:"itemId"`
+	Item      *Item    `gorm:"foreignKey:ItemID" json:"item,omitempty"`
+	Delta     Quantity `gorm:"not null" json:"delta"`
+	Reason    string   `gorm:"not null;index" json:"reason"`
+	Comment   string   `gorm:"type:text" json:"comment"`
+	Reference string   `gorm:"index" json:"reference"` // supplier / delivery document for receipts
+	Balance   Quantity `gorm:"not null" json:"balance"`
+	// LocationID is where the stock changed; nil for audit-only entries.
+	LocationID *uint `gorm:"index" json:"locationId,omitempty"`
+	// LotID is the lot the stock went into or came out of, for lot-tracked items.
+	LotID *uint `gorm:"index" json:"lotId,omitempty"`
+	// SerialID is the unit concerned, for serial-tracked items.
+	SerialID *uint `gorm:"index" json:"serialId,omitempty"`
+	// UserID and UserName identify who made the change; the name is copied
+	// so the history stays readable after the account is renamed.
+	UserID   *uint  `gorm:"index" json:"userId,omitempty"`
+	UserName string `gorm:"not null;default:''" json:"userName"`
+	// UnitCost and Cost value the change: Cost is signed like Delta, so the
+	// sum over an item's entries is the value of its stock. Withdrawals are
+	// valued from the item's cost layers; transfers carry no cost.
+	UnitCost Money `gorm:"not null;default:0" json:"unitCost"`
+	Cost     Money `gorm:"not null;default:0" json:"cost"`
+	// CostGiven marks UnitCost as set by the caller, zero included. A
+	// receipt without one is valued at the item's current average cost.
+	CostGiven bool      `gorm:"-" json:"-"`
+	CreatedAt time.Time `gorm:"index" json:"created"`
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Supplier is a company goods are ordered from.
+type Supplier struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	Name      string         `gorm:"not null;uniqueIndex:idx_suppliers_name,where:deleted_at IS NULL" json:"name"`
+	INN       string         `gorm:"not null;default:''" json:"inn"` // taxpayer number
+	Contact   string         `gorm:"not null;default:''" json:"contact"`
+	Phone     string         `gorm:"not null;default:''" json:"phone"`
+	Email     string         `gorm:"not null;default:''" json:"email"`
+	Comment   string         `gorm:"not null;default:''" json:"comment"`
+	CreatedAt time.Time      `json:"created"`
+	UpdatedAt time.Time      `json:"updated"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted,omitempty"`
+}
+
+// SupplierInput carries the editable fields of a Supplier.
+type SupplierInput struct {
+	Name    string `json:"name"`
+	INN     string `json:"inn"`
+	Contact string `json:"contact"`
+	Phone   string `json:"phone"`
+	Email   string `json:"email"`
+	Comment string `json:"comment"`
+}
+
+// Statuses of a PurchaseOrder, in lifecycle order. Only drafts can be
+// edited; goods can be received while an order is sent or partially
+// received. An order is closed once everything arrived or by hand.
+const (
+	OrderDraft   = "draft"
+	OrderSent    = "sent"
+	OrderPartial = "partially_received"
+	OrderClosed  = "closed"
+)
+
+// PurchaseOrder is an order of goods from a supplier. Its Number is the
+// reference of the receipt movements booked against it.
+type PurchaseOrder struct {
+	ID         uint   `gorm:"primaryKey" json:"id"`
+	Number     string `gorm:"not null;uniqueIndex" json:"number"`
+	SupplierID uint   `gorm:"not null;index" json:"supplierId"`
+	Status     string `gorm:"not null;default:'draft';index" json:"status"`
+	// LocationID is where goods are received unless a receipt says otherwise.
+	LocationID uint                `gorm:"not null" json:"locationId"`
+	Comment    string              `gorm:"not null;default:''" json:"comment"`
+	ExpectedAt *time.Time          `json:"expectedAt,omitempty"` // promised delivery date
+	UserID     *uint               `json:"userId,omitempty"`
+	UserName   string              `gorm:"not null;default:''" json:"userName"`
+	CreatedAt  time.Time           `json:"created"`
+	UpdatedAt  time.Time           `json:"updated"`
+	SentAt     *time.Time          `json:"sentAt,omitempty"`
+	ClosedAt   *time.Time          `json:"closedAt,omitempty"`
+	Supplier   *Supplier           `gorm:"foreignKey:SupplierID" json:"supplier,omitempty"`
+	Location   *Location           `gorm:"foreignKey:LocationID" json:"location,omitempty"`
+	Lines      []PurchaseOrderLine `gorm:"foreignKey:OrderID" json:"lines,omitempty"`
+}
+
+// PurchaseOrderLine is one item of an order.
+type PurchaseOrderLine struct {
+	ID       uint     `gorm:"primaryKey" json:"id"`
+	OrderID  uint     `gorm:"not null;index" json:"orderId"`
+	ItemID   uint     `gorm:"not null;index" json:"itemId"`
+	Ordered  Quantity `gorm:"not null" json:"ordered"`
+	Received Quantity `gorm:"not null;default:0" json:"received"`
+	Price    Money    `gorm:"not null;default:0" json:"price"` // per unit
+	// Outstanding is what is still to be delivered; filled in when loaded.
+	Outstanding Quantity `gorm:"-" json:"outstanding"`
+	Item        *Item    `gorm:"foreignKey:ItemID" json:"item,omitempty"`
+}
+
+// PurchaseOrderInput carries the editable fields of a draft order. Lines
+// replace the order's lines as a whole.
+type PurchaseOrderInput struct {
+	SupplierID uint                     `json:"supplierId"`
+	LocationID uint                     `json:"locationId"` // 0 for the default location
+	Comment    string                   `json:"comment"`
+	ExpectedAt *time.Time               `json:"expectedAt,omitempty"`
+	Lines      []PurchaseOrderLineInput `json:"lines"`
+}
+
+// PurchaseOrderLineInput is one line of a PurchaseOrderInput.
+type PurchaseOrderLineInput struct {
+	ItemID  uint     `json:"itemId"`
+	Ordered Quantity `json:"ordered"`
+	Price   Money    `json:"price"`
+}
+
+// ReceiptLine is what arrived for one order line. Lot-tracked items may name
+// the lot; serial-tracked items list the serial numbers instead of a quantity.
+type ReceiptLine struct {
+	LineID   uint      `json:"lineId"`
+	Quantity Quantity  `json:"quantity"`
+	Lot      *LotInput `json:"lot,omitempty"`
+	Serials  []string  `json:"serials,omitempty"`
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"math"
+	"strings"
+)
+
+// QuantityDecimals is the number of fractional digits a Quantity can hold.
+const QuantityDecimals = 3
+
+// QuantityScale is the number of stored units in one whole unit of measure.
+const QuantityScale = 1000
+
+// Quantity is a fixed-point decimal amount of goods. It is stored as an
+// integer count of 1/QuantityScale units, so sums and differences are exact
+// and SQL can compare and increment it directly. In JSON it is a plain
+// decimal number (1.25), which keeps integer items looking as before.
+type Quantity int64
+
+// Units returns q for a whole number of units.
+func Units(n int64) Quantity {
+	return Quantity(n * QuantityScale)
+}
+
+// ParseQuantity parses a decimal string such as "12", "-1.25" or "3,5".
+// More than QuantityDecimals fractional digits is an error.
+func ParseQuantity(s string) (Quantity, error) {
+	v, err := parseFixed(s, "quantity", QuantityDecimals)
+	return Quantity(v), err
+}
+
+// String formats q without trailing fractional zeros ("1.25", "3").
+func (q Quantity) String() string {
+	return formatFixed(int64(q), QuantityDecimals, true)
+}
+
+// Float returns q as a float64, for display and reports only.
+func (q Quantity) Float() float64 {
+	return float64(q) / QuantityScale
+}
+
+// FitsPrecision reports whether q has no more than precision fractional digits.
+func (q Quantity) FitsPrecision(precision int) bool {
+	if precision >= QuantityDecimals {
+		return true
+	}
+	if precision < 0 {
+		precision = 0
+	}
+	step := int64(math.Pow10(QuantityDecimals - precision))
+	return int64(q)%step == 0
+}
+
+// MarshalJSON encodes q as a JSON number.
+func (q Quantity) MarshalJSON() ([]byte, error) {
+	return []byte(q.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or numeric string. Numbers coming from
+// JavaScript may carry float noise (0.30000000000000004), so they are rounded
+// to QuantityDecimals; strings are parsed strictly.
+func (q *Quantity) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalFixed(b, "quantity", QuantityDecimals)
+	if err != nil {
+		return err
+	}
+	*q = Quantity(v)
+	return nil
+}
+
+// unitPrecisions holds the default number of decimals for common units of measure.
+var unitPrecisions = map[string]int{
+	"шт":    0,
+	"уп":    0,
+	"компл": 0,
+	"пар":   0,
+	"г":     0,
+	"кг":    3,
+	"т":     3,
+	"мл":    0,
+	"л":     3,
+	"мм":    0,
+	"см":    1,
+	"м":     2,
+	"м2":    2,
+	"м²":    2,
+	"м3":    3,
+	"м³":    3,
+}
+
+// UnitPrecision returns the default number of decimals for a unit of measure.
+// Unknown units are treated as countable (0 decimals).
+func UnitPrecision(unit string) int {
+	return unitPrecisions[strings.ToLower(strings.TrimSpace(strings.TrimSuffix(unit, ".")))]
+}
@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// Statuses of an IssueRequest. A pending request is approved or rejected;
+// approval reserves the stock, issuing hands it out. An approved request
+// can still be rejected, which releases the reservation.
+const (
+	RequestPending  = "pending"
+	RequestApproved = "approved"
+	RequestIssued   = "issued"
+	RequestRejected = "rejected"
+)
+
+// IssueRequest is an internal order for goods from the warehouse. Its
+// Number is the reference of the withdrawals booked when it is issued.
+type IssueRequest struct {
+	ID         uint   `gorm:"primaryKey" json:"id"`
+	Number     string `gorm:"not null;uniqueIndex" json:"number"`
+	Status     string `gorm:"not null;default:'pending';index" json:"status"`
+	Requester  string `gorm:"not null" json:"requester"`
+	Department string `gorm:"not null;default:'';index" json:"department"`
+	// LocationID is where the goods are issued from.
+	LocationID uint   `gorm:"not null" json:"locationId"`
+	Comment    string `gorm:"not null;default:''" json:"comment"`
+	// Decision is the approver's note, or the reason of a rejection.
+	Decision  string             `gorm:"not null;default:''" json:"decision"`
+	UserID    *uint              `json:"userId,omitempty"` // who registered the request
+	UserName  string             `gorm:"not null;default:''" json:"userName"`
+	DecidedBy string             `gorm:"not null;default:''" json:"decidedBy"` // who approved or rejected it
+	DecidedAt *time.Time         `json:"decidedAt,omitempty"`
+	IssuedBy  string             `gorm:"not null;default:''" json:"issuedBy"`
+	IssuedAt  *time.Time         `json:"issuedAt,omitempty"`
+	CreatedAt time.Time          `json:"created"`
+	UpdatedAt time.Time          `json:"updated"`
+	Location  *Location          `gorm:"foreignKey:LocationID" json:"location,omitempty"`
+	Lines     []IssueRequestLine `gorm:"foreignKey:RequestID" json:"lines,omitempty"`
+}
+
+// IssueRequestLine is one item of a request.
+type IssueRequestLine struct {
+	ID        uint     `gorm:"primaryKey" json:"id"`
+	RequestID uint     `gorm:"not null;index" json:"requestId"`
+	ItemID    uint     `gorm:"not null;index" json:"itemId"`
+	Quantity  Quantity `gorm:"not null" json:"quantity"`
+	Item      *Item    `gorm:"foreignKey:ItemID" json:"item,omitempty"`
+}
+
+// IssueRequestInput carries the editable fields of a pending request.
+// Lines replace the request's lines as a whole.
+type IssueRequestInput struct {
+	Requester  string                  `json:"requester"`
+	Department string                  `json:"department"`
+	LocationID uint                    `json:"locationId"` // 0 for the default location
+	Comment    string                  `json:"comment"`
+	Lines      []IssueRequestLineInput `json:"lines"`
+}
+
+// IssueRequestLineInput is one line of an IssueRequestInput.
+type IssueRequestLineInput struct {
+	ItemID   uint     `json:"itemId"`
+	Quantity Quantity `json:"quantity"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Reservation holds stock of an item for someone, e.g. a job or a customer.
+// Reserved stock stays on the shelf but is no longer available: Item.Reserved
+// is the sum of the item's reservations. A reservation ends when it is
+// released, withdrawn against or past its expiry.
+type Reservation struct {
+	ID       uint     `gorm:"primaryKey" json:"id"`
+	ItemID   uint     `gorm:"not null;index" json:"itemId"`
+	Quantity Quantity `gorm:"not null" json:"quantity"`
+	Holder   string   `gorm:"not null" json:"holder"` // who the stock is held for
+	Comment  string   `gorm:"not null;default:''" json:"comment"`
+	// ExpiresAt is when the reservation lapses by itself; nil holds it until released.
+	ExpiresAt *time.Time `gorm:"index" json:"expiresAt,omitempty"`
+	// RequestID is set for the reservations of an approved issue request,
+	// which only the request itself can issue or release.
+	RequestID *uint `gorm:"index" json:"requestId,omitempty"`
+	// LocationID is where the stock is held; nil holds it at any location.
+	LocationID *uint     `gorm:"index" json:"locationId,omitempty"`
+	UserID     *uint     `json:"userId,omitempty"`
+	UserName   string    `gorm:"not null;default:''" json:"userName"`
+	CreatedAt  time.Time `json:"created"`
+	UpdatedAt  time.Time `json:"updated"`
+	Item       *Item     `gorm:"foreignKey:ItemID" json:"item,omitempty"`
+}
+
+// ReservationInput carries the fields of a new reservation.
+type ReservationInput struct {
+	ItemID     uint       `json:"itemId"`
+	LocationID uint       `json:"locationId"` // 0 holds stock at any location
+	Quantity   Quantity   `json:"quantity"`
+	Holder     string     `json:"holder"`
+	Comment    string     `json:"comment"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+}
@@ -0,0 +1,76 @@
+package models
+
+import "time"
+
+// How LookupByCode matched a scanned code, in CodeLookup.MatchedBy.
+const (
+	MatchedBarcode = "barcode"
+	MatchedSKU     = "sku"
+	MatchedSerial  = "serial"
+)
+
+// CodeLookup is the result of resolving a scanned code. An unknown code
+// has Found false and no item, so the UI can offer to create one with it.
+type CodeLookup struct {
+	Code      string      `json:"code"`
+	Found     bool        `json:"found"`
+	MatchedBy string      `json:"matchedBy,omitempty"` // barcode, sku or serial
+	Item      *Item       `json:"item,omitempty"`
+	Serial    *ItemSerial `json:"serial,omitempty"` // set when a serial number was scanned
+}
+
+// Kinds of a ScanSession.
+const (
+	ScanWithdraw = "withdraw"
+	ScanReceive  = "receive"
+)
+
+// Statuses of a ScanSession.
+const (
+	ScanOpen      = "open"
+	ScanCommitted = "committed"
+	ScanCancelled = "cancelled"
+)
+
+// ScanSession is a pending withdrawal or receipt document filled by scanning
+// codes. Nothing changes in stock until it is committed, then all lines are
+// booked in one transaction.
+type ScanSession struct {
+	ID         uint   `gorm:"primaryKey" json:"id"`
+	Kind       string `gorm:"not null" json:"kind"` // withdraw or receive
+	Status     string `gorm:"not null;default:'open';index" json:"status"`
+	LocationID uint   `gorm:"not null" json:"locationId"`
+	Comment    string `gorm:"not null;default:''" json:"comment"`
+	// Reference is the document number written to the movements; for
+	// withdrawals it is also who receives issued serial-tracked units.
+	Reference string     `gorm:"not null;default:''" json:"reference"`
+	UserID    *uint      `json:"userId,omitempty"`
+	UserName  string     `gorm:"not null;default:''" json:"userName"`
+	CreatedAt time.Time  `json:"created"`
+	UpdatedAt time.Time  `json:"updated"`
+	ClosedAt  *time.Time `json:"closed,omitempty"` // when committed or cancelled
+	Location  *Location  `gorm:"foreignKey:LocationID" json:"location,omitempty"`
+	Lines     []ScanLine `gorm:"foreignKey:SessionID" json:"lines"`
+}
+
+// ScanLine is one item of a ScanSession. Scanning the same item again adds
+// to its quantity; every scanned serial number gets a line of its own.
+type ScanLine struct {
+	ID        uint        `gorm:"primaryKey" json:"id"`
+	SessionID uint        `gorm:"not null;index" json:"sessionId"`
+	ItemID    uint        `gorm:"not null" json:"itemId"`
+	SerialID  *uint       `json:"serialId,omitempty"`
+	Code      string      `gorm:"not null;default:''" json:"code"` // last code scanned for the line
+	Quantity  Quantity    `gorm:"not null" json:"quantity"`
+	CreatedAt time.Time   `json:"created"`
+	UpdatedAt time.Time   `json:"updated"`
+	Item      *Item       `gorm:"foreignKey:ItemID" json:"item,omitempty"`
+	Serial    *ItemSerial `gorm:"foreignKey:SerialID" json:"serial,omitempty"`
+}
+
+// ScanResult is what ScanCode returns: the lookup of the code and, when it
+// resolved to an item, the session line it went to.
+type ScanResult struct {
+	Lookup CodeLookup `json:"lookup"`
+	Line   *ScanLine  `json:"line,omitempty"`
+}
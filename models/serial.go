@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Statuses of an ItemSerial. Only units in stock count towards Item.Quantity.
+const (
+	SerialInStock    = "in_stock"
+	SerialIssued     = "issued"
+	SerialInRepair   = "in_repair"
+	SerialWrittenOff = "written_off" // final
+)
+
+// ValidSerialStatus reports whether status is one of the known statuses.
+func ValidSerialStatus(status string) bool {
+	switch status {
+	case SerialInStock, SerialIssued, SerialInRepair, SerialWrittenOff:
+		return true
+	}
+	return false
+}
+
+// ItemSerial is one individually tracked unit of a serial-tracked item.
+// Serial numbers are unique across the whole inventory.
+type ItemSerial struct {
+	ID     uint   `gorm:"primaryKey" json:"id"`
+	ItemID uint   `gorm:"not null;index" json:"itemId"`
+	Serial string `gorm:"not null;uniqueIndex" json:"serial"`
+	Status string `gorm:"not null;default:'in_stock';index" json:"status"`
+	// LocationID is where the unit is kept, or was last kept while it is
+	// issued, in repair or written off.
+	LocationID uint      `gorm:"not null" json:"locationId"`
+	IssuedTo   string    `gorm:"not null;default:''" json:"issuedTo"` // person or department holding an issued unit
+	Comment    string    `gorm:"not null;default:''" json:"comment"`
+	CreatedAt  time.Time `json:"created"`
+	UpdatedAt  time.Time `json:"updated"`
+	Item       *Item     `gorm:"foreignKey:ItemID" json:"item,omitempty"`
+	Location   *Location `gorm:"foreignKey:LocationID" json:"location,omitempty"`
+}
@@ -0,0 +1,54 @@
+package models
+
+// SettingsVersion is the current layout version of Settings.
+const SettingsVersion = 1
+
+// MinWindowWidth and MinWindowHeight are the smallest main window size the
+// layout supports.
+const (
+	MinWindowWidth  = 1024
+	MinWindowHeight = 768
+)
+
+// Settings are the user-adjustable application options, persisted as JSON
+// in the data directory. Fields marked "restart" take effect on next start.
+type Settings struct {
+	Version int `json:"version"`
+
+	// Updates
+	UpdateRepoOwner           string `json:"updateRepoOwner"`
+	UpdateRepoName            string `json:"updateRepoName"`
+	UpdateCheckIntervalHours  int    `json:"updateCheckIntervalHours"`
+	UpdateInitialDelaySeconds int    `json:"updateInitialDelaySeconds"`
+
+	// Database and backups
+	DatabaseFile        string `json:"databaseFile"` // restart
+	BackupIntervalHours int    `json:"backupIntervalHours"`
+	BackupKeepDaily     int    `json:"backupKeepDaily"`
+	BackupKeepWeekly    int    `json:"backupKeepWeekly"`
+
+	// Lots
+	ExpiryWarningDays int `json:"expiryWarningDays"` // lots expiring within this many days are reported at startup
+
+	// Main window
+	WindowWidth  int `json:"windowWidth"`
+	WindowHeight int `json:"windowHeight"`
+}
+
+// DefaultSettings returns the values used before anything is configured.
+func DefaultSettings() Settings {
+	return Settings{
+		Version:                   SettingsVersion,
+		UpdateRepoOwner:           "nineteenss",
+		UpdateRepoName:            "goods_wails_app",
+		UpdateCheckIntervalHours:  6,
+		UpdateInitialDelaySeconds: 30,
+		DatabaseFile:              "inventory.db",
+		BackupIntervalHours:       24,
+		BackupKeepDaily:           7,
+		BackupKeepWeekly:          4,
+		ExpiryWarningDays:         30,
+		WindowWidth:               MinWindowWidth,
+		WindowHeight:              MinWindowHeight,
+	}
+}
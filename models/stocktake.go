@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// Statuses of a Stocktake.
+const (
+	StocktakeOpen      = "open"
+	StocktakePosted    = "posted"
+	StocktakeCancelled = "cancelled"
+)
+
+// MovementStocktake is the reason of the adjustments a posted stocktake writes.
+const MovementStocktake = "stocktake"
+
+// Stocktake is a physical count of one location, optionally limited to a
+// category. Opening it freezes the expected quantity of every item in
+// scope; serial-tracked items are counted by serial number instead and
+// left out.
+type Stocktake struct {
+	ID         uint   `gorm:"primaryKey" json:"id"`
+	Status     string `gorm:"not null;default:'open';index" json:"status"`
+	LocationID uint   `gorm:"not null" json:"locationId"`
+	Category   string `gorm:"not null;default:''" json:"category"` // empty counts every category
+	Comment    string `gorm:"not null;default:''" json:"comment"`
+	// Reference tags the adjustment movements written on posting.
+	Reference string          `gorm:"not null;default:''" json:"reference"`
+	UserID    *uint           `json:"userId,omitempty"`
+	UserName  string          `gorm:"not null;default:''" json:"userName"`
+	CreatedAt time.Time       `json:"created"`
+	UpdatedAt time.Time       `json:"updated"`
+	ClosedAt  *time.Time      `json:"closed,omitempty"` // when posted or cancelled
+	Location  *Location       `gorm:"foreignKey:LocationID" json:"location,omitempty"`
+	Lines     []StocktakeLine `gorm:"foreignKey:StocktakeID" json:"lines,omitempty"`
+}
+
+// StocktakeLine is the count of one item. Counted stays nil until the item
+// is counted; uncounted lines are left alone when the stocktake is posted.
+type StocktakeLine struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	StocktakeID uint      `gorm:"not null;uniqueIndex:idx_stocktake_lines_item" json:"stocktakeId"`
+	ItemID      uint      `gorm:"not null;uniqueIndex:idx_stocktake_lines_item" json:"itemId"`
+	Expected    Quantity  `gorm:"not null" json:"expected"` // balance when the stocktake was opened
+	Counted     *Quantity `json:"counted"`
+	// Variance is Counted - Expected, zero while uncounted. Posting adds it
+	// to the current balance, so movements made during the count are kept.
+	Variance  Quantity  `gorm:"not null;default:0" json:"variance"`
+	CreatedAt time.Time `json:"created"`
+	UpdatedAt time.Time `json:"updated"`
+	Item      *Item     `gorm:"foreignKey:ItemID" json:"item,omitempty"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// User roles, from least to most privileged. Each role may do everything
+// the roles before it may.
+const (
+	RoleViewer      = "viewer"      // browse and export
+	RoleStorekeeper = "storekeeper" // change items and stock
+	RoleAdmin       = "admin"       // manage users, settings, backups and updates
+)
+
+var roleRank = map[string]int{RoleViewer: 1, RoleStorekeeper: 2, RoleAdmin: 3}
+
+// ValidRole reports whether role is one of the known roles.
+func ValidRole(role string) bool {
+	return roleRank[role] > 0
+}
+
+// RoleAllows reports whether a user with role may perform an action that
+// requires the role required.
+func RoleAllows(role, required string) bool {
+	return ValidRole(role) && roleRank[role] >= roleRank[required]
+}
+
+// User is an account that can log in to the application. Users are
+// disabled rather than deleted so movement history keeps its attribution.
+type User struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	Username     string     `gorm:"not null;uniqueIndex" json:"username"` // stored lower-case
+	DisplayName  string     `gorm:"not null;default:''" json:"displayName"`
+	PasswordHash string     `gorm:"not null" json:"-"`
+	Role         string     `gorm:"not null" json:"role"`
+	Disabled     bool       `gorm:"not null;default:false" json:"disabled"`
+	LastLoginAt  *time.Time `json:"lastLogin,omitempty"`
+	CreatedAt    time.Time  `json:"created"`
+	UpdatedAt    time.Time  `json:"updated"`
+}
+
+// UserInput carries the editable fields of CreateUser and UpdateUser.
+// An empty Password keeps the current one on update.
+type UserInput struct {
+	Username    string `json:"username"`
+	DisplayName string `json:"displayName"`
+	Role        string `json:"role"`
+	Password    string `json:"password"`
+	Disabled    bool   `json:"disabled"`
+}
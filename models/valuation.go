@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// Costing methods of an item, in Item.CostMethod.
+const (
+	// CostAverage values withdrawals at the weighted average cost of the
+	// stock on hand.
+	CostAverage = "average"
+	// CostFIFO values withdrawals at the cost of the oldest receipts first.
+	CostFIFO = "fifo"
+)
+
+// CostLayer is a quantity of an item still in stock together with what it
+// cost. Every receipt adds a layer; withdrawals use them up oldest first.
+// With average costing the remaining layers are merged into one on every
+// withdrawal, so all stock carries the same unit cost.
+type CostLayer struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ItemID    uint      `gorm:"not null;index" json:"itemId"`
+	Quantity  Quantity  `gorm:"not null" json:"quantity"` // still in stock
+	Value     Money     `gorm:"not null" json:"value"`    // cost of Quantity
+	CreatedAt time.Time `json:"created"`
+}
+
+// ValuationLine is the stock of one item in a ValuationReport.
+type ValuationLine struct {
+	ItemID   uint     `json:"itemId"`
+	Name     string   `json:"name"`
+	SKU      string   `json:"sku"`
+	Category string   `json:"category"`
+	Unit     string   `json:"unit"`
+	Quantity Quantity `json:"quantity"`
+	Value    Money    `json:"value"`
+	UnitCost Money    `json:"unitCost"` // Value / Quantity
+}
+
+// ValuationCategory totals the lines of one category.
+type ValuationCategory struct {
+	Category string `json:"category"` // empty for items without one
+	Items    int    `json:"items"`
+	Value    Money  `json:"value"`
+}
+
+// ValuationReport is the value of the stock on hand at a moment, per item
+// and per category, computed from the costs booked in the ledger.
+type ValuationReport struct {
+	AsOf       time.Time           `json:"asOf"`
+	Lines      []ValuationLine     `json:"lines"`
+	Categories []ValuationCategory `json:"categories"`
+	Total      Money               `json:"total"`
+}
@@ -0,0 +1,337 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"goods_wails_app/models"
+)
+
+// backupNamePattern matches files written by BackupService.Backup:
+// inventory-20060102-150405.000-<kind>.db. Older backups have no
+// milliseconds in their name.
+var backupNamePattern = regexp.MustCompile(`^inventory-(\d{8}-\d{6}(?:\.\d{3})?)-([a-z-]+)\.db$`)
+
+// backupTimeLayout parses the time in a backup name; the milliseconds, if
+// any, are read as a fractional second.
+const backupTimeLayout = "20060102-150405"
+
+// BackupDirName is the directory next to the database that holds backups.
+const BackupDirName = "backups"
+
+// backupName is the file name of a backup of kind taken at t.
+func backupName(t time.Time, kind string) string {
+	return fmt.Sprintf("inventory-%s-%s.db", t.Format(backupTimeLayout+".000"), kind)
+}
+
+// BackupService writes, verifies, rotates and restores snapshots of the database.
+type BackupService struct {
+	db  *DatabaseService
+	dir string
+	// KeepDaily and KeepWeekly are the number of most recent days / ISO weeks
+	// for which the newest backup is retained. The newest backup overall is
+	// always kept.
+	KeepDaily  int
+	KeepWeekly int
+	// interval is the time between scheduled backups taken by Run.
+	interval   time.Duration
+	reschedule chan struct{}
+	mu         sync.Mutex
+}
+
+// NewBackupService stores backups of db in dir, creating it if needed.
+func NewBackupService(db *DatabaseService, dir string) (*BackupService, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &BackupService{
+		db: db, dir: dir, KeepDaily: 7, KeepWeekly: 4,
+		interval: 24 * time.Hour, reschedule: make(chan struct{}, 1),
+	}, nil
+}
+
+// Configure sets the schedule and retention policy. A running Run loop
+// starts waiting for the new interval from now.
+func (b *BackupService) Configure(interval time.Duration, keepDaily int, keepWeekly int) {
+	b.mu.Lock()
+	b.interval = interval
+	b.KeepDaily = keepDaily
+	b.KeepWeekly = keepWeekly
+	b.mu.Unlock()
+	select {
+	case b.reschedule <- struct{}{}:
+	default:
+	}
+}
+
+// Dir returns the directory backups are written to.
+func (b *BackupService) Dir() string {
+	return b.dir
+}
+
+// Backup takes a consistent online snapshot with VACUUM INTO, verifies it
+// with PRAGMA integrity_check and applies the retention policy.
+func (b *BackupService) Backup(kind string) (models.BackupInfo, error) {
+	return b.backup(kind, "")
+}
+
+// backup implements Backup; retention never deletes the backup named protect.
+func (b *BackupService) backup(kind string, protect string) (models.BackupInfo, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	name := backupName(now, kind)
+	path := filepath.Join(b.dir, name)
+	if _, err := os.Stat(path); err == nil {
+		return models.BackupInfo{}, fmt.Errorf("backup %s already exists", name)
+	}
+	if err := b.db.DB.Exec("VACUUM INTO ?", path).Error; err != nil {
+		return models.BackupInfo{}, fmt.Errorf("snapshot: %w", err)
+	}
+	if err := verifyDatabaseFile(path); err != nil {
+		os.Remove(path)
+		return models.BackupInfo{}, err
+	}
+	info := models.BackupInfo{Name: name, Kind: kind, CreatedAt: now}
+	if st, err := os.Stat(path); err == nil {
+		info.Size = st.Size()
+	}
+	if err := b.prune(protect); err != nil {
+		log.Printf("backup retention: %v", err)
+	}
+	return info, nil
+}
+
+// List returns the available backups, newest first.
+func (b *BackupService) List() ([]models.BackupInfo, error) {
+	entries, err := os.ReadDir(b.dir)
+	if err != nil {
+		return nil, err
+	}
+	backups := []models.BackupInfo{}
+	for _, e := range entries {
+		m := backupNamePattern.FindStringSubmatch(e.Name())
+		if e.IsDir() || m == nil {
+			continue
+		}
+		created, err := time.ParseInLocation(backupTimeLayout, m[1], time.Local)
+		if err != nil {
+			continue
+		}
+		info := models.BackupInfo{Name: e.Name(), Kind: m[2], CreatedAt: created}
+		if fi, err := e.Info(); err == nil {
+			info.Size = fi.Size()
+		}
+		backups = append(backups, info)
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].CreatedAt.After(backups[j].CreatedAt) })
+	return backups, nil
+}
+
+// Restore stages the named backup to replace the live database the next
+// time it is opened, so the app must be restarted for it to take effect;
+// swapping the file under a running app would pull it from under loops and
+// calls still using the old connection. The backup is verified first, and
+// the current database is itself backed up so the restore can be undone.
+// Backups of an older schema are migrated forward when opened.
+func (b *BackupService) Restore(name string) error {
+	if !backupNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid backup name %q", name)
+	}
+	src := filepath.Join(b.dir, name)
+	if err := verifyDatabaseFile(src); err != nil {
+		return err
+	}
+	version, err := fileSchemaVersion(src)
+	if err != nil {
+		return err
+	}
+	if version > LatestSchemaVersion() {
+		return fmt.Errorf("%w (backup v%d, application v%d)", ErrSchemaTooNew, version, LatestSchemaVersion())
+	}
+	if _, err := b.backup(models.BackupPreRestore, name); err != nil {
+		return fmt.Errorf("backup before restore: %w", err)
+	}
+
+	return stageRestore(src, b.db.Path)
+}
+
+// Run takes a backup every interval until ctx is cancelled.
+func (b *BackupService) Run(ctx context.Context) {
+	for {
+		b.mu.Lock()
+		interval := b.interval
+		b.mu.Unlock()
+		timer := time.NewTimer(interval)
+		select {
+		case <-timer.C:
+			if _, err := b.Backup(models.BackupScheduled); err != nil {
+				log.Printf("scheduled backup failed: %v", err)
+			}
+		case <-b.reschedule:
+			timer.Stop()
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// prune deletes backups outside the retention window: for each of the last
+// KeepDaily days and KeepWeekly ISO weeks that have backups, only the newest
+// one is kept. The newest backup of each kind is kept as well, so the one
+// taken before an update, restore or migration outlives the startup backup
+// that follows it. The backup named protect is never deleted.
+func (b *BackupService) prune(protect string) error {
+	backups, err := b.List()
+	if err != nil || len(backups) == 0 {
+		return err
+	}
+	keep := map[string]bool{backups[0].Name: true, protect: true}
+	kinds := map[string]bool{}
+	days := map[string]bool{}
+	weeks := map[string]bool{}
+	for _, bk := range backups { // newest first
+		if !kinds[bk.Kind] {
+			kinds[bk.Kind] = true
+			keep[bk.Name] = true
+		}
+		day := bk.CreatedAt.Format("2006-01-02")
+		if !days[day] && len(days) < b.KeepDaily {
+			days[day] = true
+			keep[bk.Name] = true
+		}
+		y, w := bk.CreatedAt.ISOWeek()
+		week := fmt.Sprintf("%d-%02d", y, w)
+		if !weeks[week] && len(weeks) < b.KeepWeekly {
+			weeks[week] = true
+			keep[bk.Name] = true
+		}
+	}
+	for _, bk := range backups {
+		if keep[bk.Name] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(b.dir, bk.Name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backupBeforeMigrate writes a consistent copy of the database to the
+// backup directory and returns its path.
+func (s *DatabaseService) backupBeforeMigrate() (string, error) {
+	dir := filepath.Join(filepath.Dir(s.Path), BackupDirName)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	dst := filepath.Join(dir, backupName(time.Now(), models.BackupPreMigration))
+	if _, err := os.Stat(dst); err == nil {
+		return "", fmt.Errorf("backup %s already exists", dst)
+	}
+	if err := s.DB.Exec("VACUUM INTO ?", dst).Error; err != nil {
+		return "", err
+	}
+	return dst, nil
+}
+
+// verifyDatabaseFile runs PRAGMA integrity_check on a database file.
+func verifyDatabaseFile(path string) error {
+	db, err := openSQLite(path)
+	if err != nil {
+		return err
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		defer sqlDB.Close()
+	}
+	var result string
+	if err := db.Raw("PRAGMA integrity_check").Row().Scan(&result); err != nil {
+		return fmt.Errorf("integrity check: %w", err)
+	}
+	if result != "ok" {
+		return fmt.Errorf("integrity check of %s failed: %s", filepath.Base(path), result)
+	}
+	return nil
+}
+
+// fileSchemaVersion reads the schema version stored in a database file.
+func fileSchemaVersion(path string) (int, error) {
+	db, err := openSQLite(path)
+	if err != nil {
+		return 0, err
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		defer sqlDB.Close()
+	}
+	var version int
+	if !db.Migrator().HasTable(&schemaMigration{}) {
+		return 0, nil
+	}
+	err = db.Model(&schemaMigration{}).Select("COALESCE(MAX(version), 0)").Scan(&version).Error
+	return version, err
+}
+
+// stageRestore puts a copy of src next to the database at dbPath, for
+// applyStagedRestore to swap in when the database is next opened.
+func stageRestore(src string, dbPath string) error {
+	tmp := dbPath + ".restore.tmp"
+	if err := copyFile(src, tmp); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, dbPath+".restore"); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// applyStagedRestore replaces the database at dbPath with a backup staged
+// by Restore, if there is one. It runs before the database is opened.
+func applyStagedRestore(dbPath string) error {
+	staged := dbPath + ".restore"
+	if _, err := os.Stat(staged); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	// Stale rollback journals must not be replayed onto the restored file.
+	os.Remove(dbPath + "-journal")
+	os.Remove(dbPath + "-wal")
+	os.Remove(dbPath + "-shm")
+	if err := os.Rename(staged, dbPath); err != nil {
+		return fmt.Errorf("apply restored backup: %w", err)
+	}
+	log.Printf("restored database from backup")
+	return nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"path/filepath"
+	"testing"
+
+	"goods_wails_app/models"
+)
+
+func TestBackupsInTheSameSecond(t *testing.T) {
+	db := newTestDB(t)
+	backups, err := NewBackupService(db, t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	kinds := []string{models.BackupPreMigration, models.BackupStartup, models.BackupManual, models.BackupManual}
+	for _, kind := range kinds {
+		if _, err := backups.Backup(kind); err != nil {
+			t.Fatalf("%s backup: %v", kind, err)
+		}
+	}
+	list, err := backups.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]int{}
+	for _, bk := range list {
+		got[bk.Kind]++
+	}
+	// Retention keeps the newest backup of each kind.
+	if len(list) != 3 || got[models.BackupPreMigration] != 1 || got[models.BackupStartup] != 1 || got[models.BackupManual] != 1 {
+		t.Fatalf("kept %v", got)
+	}
+}
+
+func TestMigrateBacksUpIntoBackupDir(t *testing.T) {
+	dir := t.TempDir()
+	db, err := NewDatabaseService(dir, "inventory.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := migrations[0].Up(db.DB); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+	backups, err := NewBackupService(db, filepath.Join(dir, BackupDirName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err := backups.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Kind != models.BackupPreMigration {
+		t.Fatalf("backups after migrating: %+v", list)
+	}
+	stray, err := filepath.Glob(filepath.Join(dir, "*.bak"))
+	if err != nil || len(stray) > 0 {
+		t.Fatalf("backups left next to the database: %v %v", stray, err)
+	}
+}
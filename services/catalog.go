@@ -0,0 +1,184 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"goods_wails_app/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrPrecision is returned when a quantity has more decimals than the item's unit allows.
+	ErrPrecision = errors.New("quantity precision exceeded")
+	// ErrDuplicateSKU is returned when another item already uses the SKU.
+	ErrDuplicateSKU = errors.New("sku already in use")
+	// ErrDuplicateBarcode is returned when another item already uses the barcode.
+	ErrDuplicateBarcode = errors.New("barcode already in use")
+)
+
+// FindItemBySKU returns the item with the given SKU.
+func (s *DatabaseService) FindItemBySKU(sku string) (*models.Item, error) {
+	var item models.Item
+	err := s.DB.Preload("Barcodes").Where("sku = ?", strings.TrimSpace(sku)).First(&item).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// FindItemByBarcode returns the item carrying the given barcode.
+func (s *DatabaseService) FindItemByBarcode(code string) (*models.Item, error) {
+	var item models.Item
+	err := s.DB.Preload("Barcodes").
+		Joins("JOIN item_barcodes ON item_barcodes.item_id = items.id").
+		Where("item_barcodes.code = ?", strings.TrimSpace(code)).
+		First(&item).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// generatedSKU returns the SKU assigned to the item id when it has none:
+// SKU- and the id, or the next free number when that one was typed in by
+// hand for another item.
+func generatedSKU(tx *gorm.DB, id uint) (string, error) {
+	for n := id; ; n++ {
+		sku := fmt.Sprintf("SKU-%06d", n)
+		var used int64
+		if err := tx.Unscoped().Model(&models.Item{}).Where("sku = ? AND id <> ?", sku, id).Count(&used).Error; err != nil {
+			return "", err
+		}
+		if used == 0 {
+			return sku, nil
+		}
+	}
+}
+
+// applyCatalogInput copies the catalog fields present in in onto item.
+func applyCatalogInput(item *models.Item, in models.ItemInput) {
+	if in.SKU != nil {
+		item.SKU = strings.TrimSpace(*in.SKU)
+	}
+	if in.Unit != nil {
+		unit := strings.TrimSpace(*in.Unit)
+		if unit == "" {
+			unit = models.DefaultUnit
+		}
+		// Only a new unit brings its default precision, so re-importing an
+		// export keeps a precision set by hand.
+		if unit != item.Unit {
+			item.Unit = unit
+			item.Precision = models.UnitPrecision(unit)
+		}
+	}
+	if in.Precision != nil {
+		item.Precision = *in.Precision
+	}
+	if in.Category != nil {
+		item.Category = strings.TrimSpace(*in.Category)
+	}
+	if in.Location != nil {
+		item.Location = strings.TrimSpace(*in.Location)
+	}
+	if in.MinStock != nil {
+		item.MinStock = *in.MinStock
+	}
+	if in.ReorderQty != nil {
+		item.ReorderQty = *in.ReorderQty
+	}
+	if in.TrackLots != nil {
+		item.TrackLots = *in.TrackLots
+	}
+	if in.TrackSerials != nil {
+		item.TrackSerials = *in.TrackSerials
+	}
+	if in.CostMethod != nil {
+		item.CostMethod = strings.TrimSpace(*in.CostMethod)
+	}
+}
+
+// checkPrecision validates item.Precision and that q and the item's
+// stock thresholds fit it.
+func checkPrecision(item *models.Item, q models.Quantity) error {
+	if item.Precision < 0 || item.Precision > models.QuantityDecimals {
+		return fmt.Errorf("precision must be between 0 and %d", models.QuantityDecimals)
+	}
+	for _, v := range []models.Quantity{q, item.MinStock, item.ReorderQty} {
+		if !v.FitsPrecision(item.Precision) {
+			return fmt.Errorf("%w: %s %s allows %d decimals", ErrPrecision, v, item.Unit, item.Precision)
+		}
+	}
+	if item.MinStock < 0 || item.ReorderQty < 0 {
+		return fmt.Errorf("minimum stock and reorder quantity must not be negative")
+	}
+	return nil
+}
+
+// checkCostMethod rejects cost methods other than average and FIFO.
+func checkCostMethod(item *models.Item) error {
+	if item.CostMethod != models.CostAverage && item.CostMethod != models.CostFIFO {
+		return fmt.Errorf("unknown cost method %q", item.CostMethod)
+	}
+	return nil
+}
+
+// checkCatalogUnique verifies that the SKU and barcodes in in are not used by
+// any other item than id (0 for a new item). Soft-deleted items count too,
+// so restoring them never produces duplicates.
+func checkCatalogUnique(tx *gorm.DB, id uint, in models.ItemInput) error {
+	if in.SKU != nil {
+		if sku := strings.TrimSpace(*in.SKU); sku != "" {
+			var n int64
+			if err := tx.Unscoped().Model(&models.Item{}).Where("sku = ? AND id <> ?", sku, id).Count(&n).Error; err != nil {
+				return err
+			}
+			if n > 0 {
+				return fmt.Errorf("%w: %s", ErrDuplicateSKU, sku)
+			}
+		}
+	}
+	if in.Barcodes != nil {
+		seen := map[string]bool{}
+		for _, code := range *in.Barcodes {
+			code = strings.TrimSpace(code)
+			if code == "" {
+				continue
+			}
+			if seen[code] {
+				return fmt.Errorf("%w: %s", ErrDuplicateBarcode, code)
+			}
+			seen[code] = true
+			var n int64
+			if err := tx.Model(&models.ItemBarcode{}).Where("code = ? AND item_id <> ?", code, id).Count(&n).Error; err != nil {
+				return err
+			}
+			if n > 0 {
+				return fmt.Errorf("%w: %s", ErrDuplicateBarcode, code)
+			}
+		}
+	}
+	return nil
+}
+
+// replaceBarcodes sets the barcodes of item to codes, dropping blanks.
+func replaceBarcodes(tx *gorm.DB, item *models.Item, codes []string) error {
+	if err := tx.Where("item_id = ?", item.ID).Delete(&models.ItemBarcode{}).Error; err != nil {
+		return err
+	}
+	item.Barcodes = []models.ItemBarcode{}
+	for _, code := range codes {
+		code = strings.TrimSpace(code)
+		if code == "" {
+			continue
+		}
+		item.Barcodes = append(item.Barcodes, models.ItemBarcode{ItemID: item.ID, Code: code})
+	}
+	if len(item.Barcodes) == 0 {
+		return nil
+	}
+	return tx.Create(&item.Barcodes).Error
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// DataDirFlag overrides the data directory on the command line: --data-dir=PATH.
+	DataDirFlag = "--data-dir"
+	// DataDirEnv overrides the data directory through the environment.
+	DataDirEnv = "GOODS_DATA_DIR"
+	// LaunchConfigName is an optional JSON file next to the executable,
+	// {"dataDir": "PATH"}; relative paths are resolved against the executable.
+	LaunchConfigName = "goods_wails_app.json"
+	// PortableMarker next to the executable switches to portable mode: data
+	// is kept in a "data" folder beside the executable.
+	PortableMarker = "portable"
+	// appDirName is the folder created under the OS user config directory.
+	appDirName = "goods_wails_app"
+	// DatabaseFileName is the SQLite file older versions kept next to the
+	// executable and the default name inside the data directory.
+	DatabaseFileName = "inventory.db"
+)
+
+// Data directory sources reported by ResolveDataDir, highest priority first.
+const (
+	DataDirFromFlag     = "flag"
+	DataDirFromEnv      = "env"
+	DataDirFromConfig   = "config"
+	DataDirFromPortable = "portable"
+	DataDirFromDefault  = "default"
+)
+
+type launchConfig struct {
+	DataDir string `json:"dataDir"`
+}
+
+// ResolveDataDir decides where the database and backups live. In order:
+// the --data-dir flag in args, the GOODS_DATA_DIR variable, the launch
+// config file next to the executable, portable mode, and finally the OS
+// user config directory (%AppData%\goods_wails_app on Windows). It returns
+// the absolute directory and which source chose it; the directory is not
+// created.
+func ResolveDataDir(exeDir string, args []string) (string, string, error) {
+	if dir := flagValue(args, DataDirFlag); dir != "" {
+		return absFrom(exeDir, dir), DataDirFromFlag, nil
+	}
+	if dir := strings.TrimSpace(os.Getenv(DataDirEnv)); dir != "" {
+		return absFrom(exeDir, dir), DataDirFromEnv, nil
+	}
+	raw, err := os.ReadFile(filepath.Join(exeDir, LaunchConfigName))
+	if err == nil {
+		var cfg launchConfig
+		if err := json.Unmarshal(raw, &cfg); err != nil {
+			return "", "", fmt.Errorf("%s: %w", LaunchConfigName, err)
+		}
+		if dir := strings.TrimSpace(cfg.DataDir); dir != "" {
+			return absFrom(exeDir, dir), DataDirFromConfig, nil
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", "", err
+	}
+	if _, err := os.Stat(filepath.Join(exeDir, PortableMarker)); err == nil {
+		return filepath.Join(exeDir, "data"), DataDirFromPortable, nil
+	}
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", "", fmt.Errorf("user config dir: %w", err)
+	}
+	return filepath.Join(base, appDirName), DataDirFromDefault, nil
+}
+
+// MigrateLegacyData moves the inventory.db that older versions kept next to
+// the executable into dataDir as dbFile, together with its backups folder.
+// It does nothing when dataDir already has a database or there is nothing to
+// move. The old file is renamed to inventory.db.migrated rather than
+// deleted; if the executable folder is read-only it is left in place.
+func MigrateLegacyData(exeDir string, dataDir string, dbFile string) error {
+	legacy := filepath.Join(exeDir, DatabaseFileName)
+	target := filepath.Join(dataDir, dbFile)
+	if samePath(legacy, target) {
+		return nil
+	}
+	if _, err := os.Stat(target); err == nil {
+		return nil
+	}
+	if _, err := os.Stat(legacy); err != nil {
+		return nil
+	}
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		return err
+	}
+	if err := verifyDatabaseFile(legacy); err != nil {
+		return fmt.Errorf("legacy database: %w", err)
+	}
+
+	// Copy rather than rename: the folders may be on different volumes and
+	// a half-moved database must never replace the original.
+	tmp := target + ".partial"
+	if err := copyFile(legacy, tmp); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := verifyDatabaseFile(tmp); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, target); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(legacy, legacy+".migrated"); err != nil {
+		log.Printf("moved database to %s; could not rename the old copy: %v", target, err)
+	}
+
+	oldBackups := filepath.Join(exeDir, BackupDirName)
+	newBackups := filepath.Join(dataDir, BackupDirName)
+	if entries, err := os.ReadDir(oldBackups); err == nil {
+		if err := os.MkdirAll(newBackups, 0o755); err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if e.IsDir() || !backupNamePattern.MatchString(e.Name()) {
+				continue
+			}
+			src := filepath.Join(oldBackups, e.Name())
+			if err := copyFile(src, filepath.Join(newBackups, e.Name())); err != nil {
+				return err
+			}
+			os.Remove(src)
+		}
+		os.Remove(oldBackups) // only succeeds when empty
+	}
+	log.Printf("moved database from %s to %s", legacy, target)
+	return nil
+}
+
+// flagValue returns the value of --name=value or --name value in args.
+func flagValue(args []string, name string) string {
+	for i, arg := range args {
+		if v, ok := strings.CutPrefix(arg, name+"="); ok {
+			return strings.TrimSpace(v)
+		}
+		if arg == name && i+1 < len(args) {
+			return strings.TrimSpace(args[i+1])
+		}
+	}
+	return ""
+}
+
+func absFrom(base string, path string) string {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(base, path)
+	}
+	return filepath.Clean(path)
+}
+
+func samePath(a, b string) bool {
+	ai, errA := os.Stat(a)
+	bi, errB := os.Stat(b)
+	if errA == nil && errB == nil {
+		return os.SameFile(ai, bi)
+	}
+	return filepath.Clean(a) == filepath.Clean(b)
+}
@@ -1,24 +1,125 @@
 package services
 
 import (
+	"database/sql/driver"
 	"fmt"
+	"log"
 	"path/filepath"
+	"strings"
+	"sync"
 
-    "github.com/glebarez/sqlite"
+	"goods_wails_app/models"
+
+	gosqlite "github.com/glebarez/go-sqlite"
+	"github.com/glebarez/sqlite"
 	"gorm.io/gorm"
 )
 
 // DatabaseService encapsulates GORM DB instance.
 type DatabaseService struct {
-	DB *gorm.DB
+	DB   *gorm.DB
+	Path string // database file location, used for backups
+	// OnLowStock, if set, is called after a committed change leaves an item
+	// at or below its minimum stock when it was above it before.
+	OnLowStock func(item models.Item)
+	// Actor, if set, returns the logged-in user; every stock movement is
+	// attributed to them.
+	Actor func() *models.User
 }
 
 // NewDatabaseService initializes a SQLite database in the given directory.
 func NewDatabaseService(appDataDir string, dbName string) (*DatabaseService, error) {
 	dbPath := filepath.Join(appDataDir, dbName)
-	db, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
+	if err := applyStagedRestore(dbPath); err != nil {
+		return nil, err
+	}
+	db, err := openSQLite(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	s := &DatabaseService{Path: dbPath}
+	s.attach(db)
+	return s, nil
+}
+
+// attach makes db the service's connection and installs the callback that
+// stamps every new stock movement with the acting user.
+func (s *DatabaseService) attach(db *gorm.DB) {
+	err := db.Callback().Create().Before("gorm:create").Register("app:movement_user", s.stampMovementUser)
+	if err != nil {
+		log.Printf("register movement callback: %v", err)
+	}
+	s.DB = db
+}
+
+func (s *DatabaseService) stampMovementUser(db *gorm.DB) {
+	m, ok := db.Statement.Dest.(*models.StockMovement)
+	if !ok || m.UserID != nil {
+		return
+	}
+	m.UserID, m.UserName = s.actor()
+}
+
+// actor returns the id and username of the logged-in user, or nil and ""
+// when nobody is.
+func (s *DatabaseService) actor() (*uint, string) {
+	if s.Actor == nil {
+		return nil, ""
+	}
+	user := s.Actor()
+	if user == nil {
+		return nil, ""
+	}
+	id := user.ID
+	return &id, user.Username
+}
+
+// Close closes the underlying connection pool.
+func (s *DatabaseService) Close() error {
+	sqlDB, err := s.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+// registerFunctions registers the custom SQL functions once per process;
+// the driver keeps them for every connection opened afterwards.
+var registerFunctions sync.Once
+
+// registerSQLFunctions adds the functions queries and migrations rely on.
+// SQLite's built-in lower() only folds ASCII, which makes searching
+// Cyrillic names case-sensitive. unicode_lower folds the full range.
+func registerSQLFunctions() {
+	gosqlite.MustRegisterDeterministicScalarFunction("unicode_lower", 1, func(_ *gosqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		switch v := args[0].(type) {
+		case string:
+			return strings.ToLower(v), nil
+		case []byte:
+			return strings.ToLower(string(v)), nil
+		default:
+			return v, nil
+		}
+	})
+}
+
+func openSQLite(dbPath string) (*gorm.DB, error) {
+	// Migrations use unicode_lower too, so it has to exist before the
+	// connection is handed to anything.
+	registerFunctions.Do(registerSQLFunctions)
+	// busy_timeout lets writers wait for a lock held by another process instead of failing.
+	dsn := dbPath + "?_pragma=busy_timeout(5000)"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	if err != nil {
+		return nil, fmt.Errorf("open db: %w", err)
+	}
+	sqlDB, err := db.DB()
 	if err != nil {
 		return nil, fmt.Errorf("open db: %w", err)
 	}
-	return &DatabaseService{DB: db}, nil
+	// SQLite allows a single writer. One pooled connection serializes every
+	// transaction inside this process, so read-modify-write sequences cannot
+	// interleave or fail with SQLITE_BUSY on lock upgrade.
+	sqlDB.SetMaxOpenConns(1)
+	return db, nil
 }
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"goods_wails_app/models"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/xuri/excelize/v2"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// exportHeader is the column layout of CSV and XLSX exports. The titles are
+// recognised by ImportItems, so an export can be edited and imported back.
+var exportHeader = []string{
+	"Артикул", "Наименование", "Количество", "Ед. изм.", "Категория",
+	"Место хранения", "Штрихкоды", "Мин. остаток", "Комментарий", "Обновлено",
+}
+
+// exportDecimalColumns are the exportHeader columns holding decimal numbers.
+var exportDecimalColumns = []int{2, 7}
+
+// ExportItems writes the items matching filter to path as CSV, XLSX or PDF
+// and returns how many items were written. Sorting follows filter; paging
+// is ignored so the whole filtered list is exported.
+func (s *DatabaseService) ExportItems(format string, path string, filter models.ItemQuery) (int, error) {
+	filter.Offset, filter.Limit = 0, 0
+	var items []models.Item
+	err := s.DB.Preload("Barcodes").Scopes(itemFilter(filter), itemOrder(filter)).Find(&items).Error
+	if err != nil {
+		return 0, err
+	}
+	switch strings.ToLower(format) {
+	case models.FormatCSV:
+		err = writeItemsCSV(path, items)
+	case models.FormatXLSX:
+		err = writeItemsXLSX(path, items)
+	case models.FormatPDF:
+		err = writeItemsPDF(path, items, time.Now())
+	default:
+		err = fmt.Errorf("unsupported export format %q", format)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(items), nil
+}
+
+// exportRow returns the CSV/XLSX cells of an item in exportHeader order.
+func exportRow(it models.Item) []string {
+	codes := make([]string, 0, len(it.Barcodes))
+	for _, b := range it.Barcodes {
+		codes = append(codes, b.Code)
+	}
+	minStock := ""
+	if it.MinStock > 0 {
+		minStock = it.MinStock.String()
+	}
+	return []string{
+		it.SKU, it.Name, it.Quantity.String(), it.Unit, it.Category,
+		it.Location, strings.Join(codes, " "), minStock, it.Comment,
+		it.UpdatedAt.Local().Format("02.01.2006 15:04"),
+	}
+}
+
+// writeItemsCSV writes UTF-8 with a BOM and ';' separators, which is what
+// Excel with Russian regional settings opens without an import wizard.
+func writeItemsCSV(path string, items []models.Item) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(f)
+	bw.WriteString("\ufeff")
+	w := csv.NewWriter(bw)
+	w.Comma = ';'
+	w.Write(exportHeader)
+	for _, it := range items {
+		row := exportRow(it)
+		// Decimal comma, matching the separator choice above.
+		for _, c := range exportDecimalColumns {
+			row[c] = strings.Replace(row[c], ".", ",", 1)
+		}
+		w.Write(row)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func writeItemsXLSX(path string, items []models.Item) error {
+	f := excelize.NewFile()
+	defer f.Close()
+	sheet := "Остатки"
+	f.SetSheetName(f.GetSheetName(0), sheet)
+
+	header := make([]interface{}, len(exportHeader))
+	for i, h := range exportHeader {
+		header[i] = h
+	}
+	if err := f.SetSheetRow(sheet, "A1", &header); err != nil {
+		return err
+	}
+	for i, it := range items {
+		cells := exportRow(it)
+		row := make([]interface{}, len(cells))
+		for j, c := range cells {
+			row[j] = c
+		}
+		// Numeric cells stay numbers so the sheet can be summed and sorted.
+		row[2] = it.Quantity.Float()
+		if it.MinStock > 0 {
+			row[7] = it.MinStock.Float()
+		}
+		cell, _ := excelize.CoordinatesToCellName(1, i+2)
+		if err := f.SetSheetRow(sheet, cell, &row); err != nil {
+			return err
+		}
+	}
+	f.SetColWidth(sheet, "A", "A", 14)
+	f.SetColWidth(sheet, "B", "B", 40)
+	f.SetColWidth(sheet, "E", "G", 18)
+	f.SetColWidth(sheet, "I", "I", 40)
+	f.SetColWidth(sheet, "J", "J", 17)
+	f.SetPanes(sheet, &excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"})
+	return f.SaveAs(path)
+}
+
+// writeItemsPDF renders an A4 stock sheet with an empty "Факт" column for
+// physical counts.
+func writeItemsPDF(path string, items []models.Item, now time.Time) error {
+	t := pdfTable{
+		title:  "Остатки на складе",
+		header: []string{fmt.Sprintf("Сформировано %s, позиций: %d", now.Format("02.01.2006 15:04"), len(items))},
+		columns: []pdfColumn{
+			{"№", 10, "R"}, {"Артикул", 28, "L"}, {"Наименование", 72, "L"},
+			{"Место", 25, "L"}, {"Кол-во", 20, "R"}, {"Ед.", 12, "L"}, {"Факт", 23, "L"},
+		},
+	}
+	for i, it := range items {
+		t.rows = append(t.rows, []string{
+			fmt.Sprint(i + 1), it.SKU, it.Name, it.Location, it.Quantity.String(), it.Unit, "",
+		})
+	}
+	return writeTablePDF(path, t)
+}
+
+// pdfColumn is a column of a pdfTable.
+type pdfColumn struct {
+	title string
+	width float64 // mm
+	align string
+}
+
+// pdfTable is an A4 report: a title, a few lines of header text and a table.
+type pdfTable struct {
+	title   string
+	header  []string
+	columns []pdfColumn
+	rows    [][]string
+	empty   string // printed instead of the rows when there are none
+}
+
+// writeTablePDF renders t with page numbers, repeating the column titles on
+// every page and cutting cells that do not fit. Go fonts are embedded
+// because the standard PDF fonts have no Cyrillic glyphs.
+func writeTablePDF(path string, t pdfTable) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.AddUTF8FontFromBytes("Go", "", goregular.TTF)
+	pdf.AddUTF8FontFromBytes("Go", "B", gobold.TTF)
+	pdf.SetMargins(10, 12, 10)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.SetTitle(t.title, true)
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont("Go", "", 8)
+		pdf.CellFormat(0, 5, fmt.Sprintf("Стр. %d/{nb}", pdf.PageNo()), "", 0, "R", false, 0, "")
+	})
+	pdf.AliasNbPages("")
+
+	const rowH = 6.5
+	drawHeader := func() {
+		pdf.SetFont("Go", "B", 9)
+		pdf.SetFillColor(230, 230, 230)
+		for _, c := range t.columns {
+			pdf.CellFormat(c.width, rowH, c.title, "1", 0, "C", true, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont("Go", "", 9)
+	}
+
+	pdf.AddPage()
+	pdf.SetFont("Go", "B", 14)
+	pdf.CellFormat(0, 8, t.title, "", 1, "L", false, 0, "")
+	pdf.SetFont("Go", "", 9)
+	pageW, pageH := pdf.GetPageSize()
+	left, _, right, bottom := pdf.GetMargins()
+	for _, line := range t.header {
+		pdf.CellFormat(0, 5, fitText(pdf, line, pageW-left-right), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(2)
+	drawHeader()
+
+	for _, row := range t.rows {
+		if pdf.GetY()+rowH > pageH-bottom {
+			pdf.AddPage()
+			drawHeader()
+		}
+		for j, c := range t.columns {
+			pdf.CellFormat(c.width, rowH, fitText(pdf, row[j], c.width-2), "1", 0, c.align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+	if len(t.rows) == 0 && t.empty != "" {
+		pdf.CellFormat(0, rowH, t.empty, "1", 1, "C", false, 0, "")
+	}
+	return pdf.OutputFileAndClose(path)
+}
+
+// fitText shortens s with an ellipsis until it fits width at the current font.
+func fitText(pdf *fpdf.Fpdf, s string, width float64) string {
+	if pdf.GetStringWidth(s) <= width {
+		return s
+	}
+	r := []rune(s)
+	for len(r) > 0 && pdf.GetStringWidth(string(r)+"…") > width {
+		r = r[:len(r)-1]
+	}
+	return string(r) + "…"
+}
@@ -0,0 +1,323 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"goods_wails_app/models"
+
+	"github.com/xuri/excelize/v2"
+	"golang.org/x/text/encoding/charmap"
+	"gorm.io/gorm"
+)
+
+// errImportRollback aborts the import transaction for dry runs and rejected files.
+var errImportRollback = errors.New("import rolled back")
+
+// importHeaderAliases lists the header titles recognised for each field when
+// ImportOptions.Columns does not map it. Compared case-insensitively.
+var importHeaderAliases = map[string][]string{
+	models.FieldName:       {"name", "наименование", "название", "товар"},
+	models.FieldQuantity:   {"quantity", "qty", "количество", "кол-во", "остаток"},
+	models.FieldComment:    {"comment", "комментарий", "примечание"},
+	models.FieldSKU:        {"sku", "артикул", "код"},
+	models.FieldBarcodes:   {"barcode", "barcodes", "штрихкод", "штрих-код", "штрихкоды"},
+	models.FieldUnit:       {"unit", "ед.", "ед. изм.", "ед.изм.", "единица измерения", "единица"},
+	models.FieldCategory:   {"category", "категория", "группа"},
+	models.FieldLocation:   {"location", "место", "место хранения", "ячейка"},
+	models.FieldMinStock:   {"min stock", "минимальный остаток", "мин. остаток", "минимум"},
+	models.FieldReorderQty: {"reorder qty", "заказ", "количество заказа"},
+}
+
+// ImportItems reads a CSV or XLSX catalog and upserts its rows in one
+// transaction. Rows find existing items by name or SKU (opts.MatchBy).
+// If any row is invalid, or opts.DryRun is set, the transaction is rolled
+// back and the result only reports what would have happened.
+func (s *DatabaseService) ImportItems(path string, opts models.ImportOptions) (models.ImportResult, error) {
+	result := models.ImportResult{DryRun: opts.DryRun, Errors: []models.ImportRowError{}}
+	rows, err := readImportRows(path, opts)
+	if err != nil {
+		return result, err
+	}
+	if len(rows) == 0 {
+		return result, fmt.Errorf("file is empty")
+	}
+	cols, err := mapImportColumns(rows[0], opts.Columns)
+	if err != nil {
+		return result, err
+	}
+	matchBy := opts.MatchBy
+	if matchBy == "" {
+		matchBy = models.MatchByName
+	}
+	if matchBy != models.MatchByName && matchBy != models.MatchBySKU {
+		return result, fmt.Errorf("unknown match mode %q", opts.MatchBy)
+	}
+	if _, ok := cols[matchBy]; !ok {
+		return result, fmt.Errorf("no %s column to match items by", matchBy)
+	}
+	reference := filepath.Base(path)
+
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		for i, row := range rows[1:] {
+			rowNum := i + 2
+			if isBlankRow(row) {
+				result.Skipped++
+				continue
+			}
+			// Each row runs in a savepoint so a failing row does not
+			// poison the statements of the rows after it.
+			var created bool
+			err := tx.Transaction(func(rtx *gorm.DB) error {
+				var err error
+				created, err = importRow(rtx, row, cols, matchBy, reference, opts.LocationID)
+				return err
+			})
+			if err != nil {
+				result.Errors = append(result.Errors, models.ImportRowError{Row: rowNum, Message: err.Error()})
+				continue
+			}
+			if created {
+				result.Created++
+			} else {
+				result.Updated++
+			}
+		}
+		if opts.DryRun || len(result.Errors) > 0 {
+			return errImportRollback
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errImportRollback) {
+		return result, err
+	}
+	result.Applied = err == nil
+	return result, nil
+}
+
+// importRow upserts a single row and reports whether a new item was created.
+func importRow(tx *gorm.DB, row []string, cols map[string]int, matchBy string, reference string, locationID uint) (bool, error) {
+	cell := func(field string) (string, bool) {
+		idx, ok := cols[field]
+		if !ok || idx >= len(row) {
+			return "", ok
+		}
+		return strings.TrimSpace(row[idx]), true
+	}
+
+	key, _ := cell(matchBy)
+	if key == "" {
+		return false, fmt.Errorf("empty %s", matchBy)
+	}
+	var existing models.Item
+	err := tx.Where(matchBy+" = ?", key).First(&existing).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, err
+	}
+	found := err == nil
+
+	in := models.ItemInput{StockLocationID: locationID}
+	if found {
+		in.Name = existing.Name
+		in.Quantity = existing.Quantity
+		in.Comment = existing.Comment
+	}
+	if v, ok := cell(models.FieldName); ok {
+		in.Name = v
+	}
+	if in.Name == "" {
+		return false, fmt.Errorf("empty name")
+	}
+	if v, ok := cell(models.FieldQuantity); ok && v != "" {
+		q, err := parseImportQuantity(v)
+		if err != nil {
+			return false, err
+		}
+		if q < 0 {
+			return false, fmt.Errorf("negative quantity %s", q)
+		}
+		in.Quantity = q
+	}
+	if v, ok := cell(models.FieldComment); ok {
+		in.Comment = v
+	}
+	for field, dst := range map[string]**string{
+		models.FieldSKU:      &in.SKU,
+		models.FieldUnit:     &in.Unit,
+		models.FieldCategory: &in.Category,
+		models.FieldLocation: &in.Location,
+	} {
+		if v, ok := cell(field); ok {
+			v := v
+			*dst = &v
+		}
+	}
+	if v, ok := cell(models.FieldBarcodes); ok {
+		codes := strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ';' || r == ' ' })
+		in.Barcodes = &codes
+	}
+	for field, dst := range map[string]**models.Quantity{
+		models.FieldMinStock:   &in.MinStock,
+		models.FieldReorderQty: &in.ReorderQty,
+	} {
+		if v, ok := cell(field); ok && v != "" {
+			q, err := parseImportQuantity(v)
+			if err != nil {
+				return false, err
+			}
+			*dst = &q
+		}
+	}
+
+	if found {
+		_, _, err = updateItem(tx, existing.ID, in, models.MovementImport, reference)
+		return false, err
+	}
+	_, err = createItem(tx, in, models.MovementImport, reference)
+	return true, err
+}
+
+// parseImportQuantity parses a quantity cell such as "1 234,5". Thousands
+// may be grouped with spaces, including the non-breaking ones (U+00A0,
+// U+202F) Excel and LibreOffice write for Russian locales.
+func parseImportQuantity(v string) (models.Quantity, error) {
+	return models.ParseQuantity(strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\u00a0', '\u202f':
+			return -1
+		}
+		return r
+	}, v))
+}
+
+// mapImportColumns resolves each item field to a column index of header.
+func mapImportColumns(header []string, explicit map[string]string) (map[string]int, error) {
+	index := map[string]int{}
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		if _, dup := index[h]; !dup && h != "" {
+			index[h] = i
+		}
+	}
+	cols := map[string]int{}
+	for field, title := range explicit {
+		if _, known := importHeaderAliases[field]; !known {
+			return nil, fmt.Errorf("unknown field %q", field)
+		}
+		i, ok := index[strings.ToLower(strings.TrimSpace(title))]
+		if !ok {
+			return nil, fmt.Errorf("column %q not found", title)
+		}
+		cols[field] = i
+	}
+	for field, aliases := range importHeaderAliases {
+		if _, ok := cols[field]; ok {
+			continue
+		}
+		for _, alias := range aliases {
+			if i, ok := index[alias]; ok {
+				cols[field] = i
+				break
+			}
+		}
+	}
+	return cols, nil
+}
+
+// readImportRows returns all rows of the file, header first.
+func readImportRows(path string, opts models.ImportOptions) ([][]string, error) {
+	format := strings.ToLower(opts.Format)
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+	switch format {
+	case models.FormatCSV, "txt", "tsv":
+		return readCSVRows(path, opts)
+	case models.FormatXLSX:
+		return readXLSXRows(path, opts.Sheet)
+	default:
+		return nil, fmt.Errorf("unsupported import format %q", format)
+	}
+}
+
+func readCSVRows(path string, opts models.ImportOptions) ([][]string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	raw = bytes.TrimPrefix(raw, []byte("\xef\xbb\xbf"))
+
+	encoding := strings.ToLower(opts.Encoding)
+	if encoding == "" || encoding == models.EncodingAuto {
+		encoding = models.EncodingUTF8
+		if !utf8.Valid(raw) {
+			encoding = models.EncodingWindows1251
+		}
+	}
+	var text io.Reader = bytes.NewReader(raw)
+	switch encoding {
+	case models.EncodingUTF8, "utf8":
+	case models.EncodingWindows1251, "cp1251":
+		text = charmap.Windows1251.NewDecoder().Reader(text)
+	default:
+		return nil, fmt.Errorf("unsupported encoding %q", opts.Encoding)
+	}
+
+	br := bufio.NewReader(text)
+	delim := []rune(opts.Delimiter)
+	if len(delim) == 0 {
+		first, _ := br.Peek(4096)
+		delim = []rune{detectDelimiter(string(first))}
+	}
+	if len(delim) != 1 {
+		return nil, fmt.Errorf("delimiter must be a single character")
+	}
+	r := csv.NewReader(br)
+	r.Comma = delim[0]
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	return r.ReadAll()
+}
+
+// detectDelimiter picks the most frequent of ; , and tab in the first line.
+// Russian Excel writes ';' by default, so it wins ties.
+func detectDelimiter(sample string) rune {
+	line, _, _ := strings.Cut(sample, "\n")
+	best, bestCount := ';', strings.Count(line, ";")
+	for _, c := range []rune{',', '\t'} {
+		if n := strings.Count(line, string(c)); n > bestCount {
+			best, bestCount = c, n
+		}
+	}
+	return best
+}
+
+func readXLSXRows(path string, sheet string) ([][]string, error) {
+	f, err := excelize.OpenFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	if sheet == "" {
+		sheet = f.GetSheetName(0)
+	}
+	// Raw values keep numbers unformatted ("1234.5" rather than "1 234,50").
+	return f.GetRows(sheet, excelize.Options{RawCellValue: true})
+}
+
+func isBlankRow(row []string) bool {
+	for _, c := range row {
+		if strings.TrimSpace(c) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"goods_wails_app/models"
+)
+
+func TestParseImportQuantity(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "12", want: "12"},
+		{in: "2,75", want: "2.75"},
+		{in: "0.5", want: "0.5"},
+		{in: "1 234,5", want: "1234.5"},
+		{in: "1\u00a0234,5", want: "1234.5"},
+		{in: "12\u202f345\u202f678", want: "12345678"},
+		{in: "12 шт", wantErr: true},
+		{in: "1,2,3", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseImportQuantity(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%q: got %s, want an error", tt.in, got)
+			}
+			continue
+		}
+		want, _ := models.ParseQuantity(tt.want)
+		if err != nil || got != want {
+			t.Errorf("%q: got %s, %v; want %s", tt.in, got, err, want)
+		}
+	}
+}
+
+func TestDetectDelimiter(t *testing.T) {
+	tests := []struct {
+		header string
+		want   rune
+	}{
+		{"Наименование;Количество;Артикул\nБолт;1,5;B-1", ';'},
+		{"name,quantity,sku", ','},
+		{"name\tquantity\tsku", '\t'},
+		{"Наименование", ';'},
+		{"a;b,c", ';'},
+	}
+	for _, tt := range tests {
+		if got := detectDelimiter(tt.header); got != tt.want {
+			t.Errorf("%q: got %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestImportItemsCSV(t *testing.T) {
+	db := newTestDB(t)
+	path := filepath.Join(t.TempDir(), "остатки.csv")
+	csv := "\ufeffНаименование;Артикул;Кол-во;Ед. изм.;Мин. остаток\n" +
+		"Кабель ВВГ;K-1;1\u00a0250,5;м;1\u202f000\n" +
+		"Болт М8;B-8;3 000;шт;\n" +
+		";;;;\n"
+	if err := os.WriteFile(path, []byte(csv), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	result, err := db.ImportItems(path, models.ImportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Applied || result.Created != 2 || result.Skipped != 1 || len(result.Errors) != 0 {
+		t.Fatalf("result %+v", result)
+	}
+	want := map[string][2]string{"K-1": {"1250.5", "1000"}, "B-8": {"3000", "0"}}
+	for sku, w := range want {
+		var item models.Item
+		if err := db.DB.Where("sku = ?", sku).First(&item).Error; err != nil {
+			t.Fatal(err)
+		}
+		qty, _ := models.ParseQuantity(w[0])
+		minStock, _ := models.ParseQuantity(w[1])
+		if item.Quantity != qty || item.MinStock != minStock {
+			t.Errorf("%s: quantity %s, min stock %s; want %s, %s", sku, item.Quantity, item.MinStock, qty, minStock)
+		}
+		checkLedger(t, db, item.ID, qty)
+	}
+}
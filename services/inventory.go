@@ -0,0 +1,441 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"goods_wails_app/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInsufficientQuantity is returned when a withdrawal exceeds the stock on hand.
+var ErrInsufficientQuantity = errors.New("insufficient quantity")
+
+// CreateItem inserts a new item and records its opening balance in the ledger.
+func (s *DatabaseService) CreateItem(in models.ItemInput) (*models.Item, error) {
+	var item *models.Item
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		item, err = createItem(tx, in, models.MovementCreate, "")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.notifyLowStock(item, false)
+	return item, nil
+}
+
+// UpdateItem overwrites item fields. A change of quantity is booked as an adjustment.
+func (s *DatabaseService) UpdateItem(id uint, in models.ItemInput) (*models.Item, error) {
+	var item *models.Item
+	var wasLow bool
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		item, wasLow, err = updateItem(tx, id, in, models.MovementAdjust, "")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.notifyLowStock(item, wasLow)
+	return item, nil
+}
+
+// createItem inserts an item built from in. A non-zero opening balance is
+// booked with the given movement reason and reference.
+func createItem(tx *gorm.DB, in models.ItemInput, reason string, reference string) (*models.Item, error) {
+	item := &models.Item{
+		Name:       in.Name,
+		Quantity:   in.Quantity,
+		Comment:    in.Comment,
+		Unit:       models.DefaultUnit,
+		CostMethod: models.CostAverage,
+		UpdatedAt:  time.Now(),
+	}
+	applyCatalogInput(item, in)
+	if err := checkPrecision(item, item.Quantity); err != nil {
+		return nil, err
+	}
+	if err := checkCostMethod(item); err != nil {
+		return nil, err
+	}
+	if err := checkTrackingMode(tx, item, false); err != nil {
+		return nil, err
+	}
+	if err := checkCatalogUnique(tx, 0, in); err != nil {
+		return nil, err
+	}
+	if err := tx.Omit(clause.Associations).Create(item).Error; err != nil {
+		return nil, err
+	}
+	if item.SKU == "" {
+		sku, err := generatedSKU(tx, item.ID)
+		if err != nil {
+			return nil, err
+		}
+		item.SKU = sku
+		if err := tx.Model(item).Update("sku", item.SKU).Error; err != nil {
+			return nil, err
+		}
+	}
+	if in.Barcodes != nil {
+		if err := replaceBarcodes(tx, item, *in.Barcodes); err != nil {
+			return nil, err
+		}
+	}
+	if item.Quantity == 0 {
+		return item, nil
+	}
+	loc, err := quantityLocation(tx, item.ID, in.StockLocationID)
+	if err != nil {
+		return nil, err
+	}
+	m := &models.StockMovement{
+		Delta:      item.Quantity,
+		Reason:     reason,
+		Comment:    item.Comment,
+		Reference:  reference,
+		LocationID: &loc,
+	}
+	err = recordMovement(tx, item, withUnitCost(m, in.UnitCost))
+	return item, err
+}
+
+// updateItem applies in to the item with the given id. A change of quantity
+// is booked with the given movement reason and reference at the location
+// quantityLocation picks for in.StockLocationID. wasLow reports
+// whether the item was at or below its minimum stock before the update.
+func updateItem(tx *gorm.DB, id uint, in models.ItemInput, reason string, reference string) (item *models.Item, wasLow bool, err error) {
+	item = &models.Item{}
+	if err := tx.Preload("Barcodes").First(item, id).Error; err != nil {
+		return nil, false, err
+	}
+	wasLow = item.IsLowStock()
+	if err := checkCatalogUnique(tx, id, in); err != nil {
+		return nil, false, err
+	}
+	delta := in.Quantity - item.Quantity
+	trackedLots, trackedSerials := item.TrackLots, item.TrackSerials
+	item.Name = in.Name
+	item.Quantity = in.Quantity
+	item.Comment = in.Comment
+	applyCatalogInput(item, in)
+	if err := checkPrecision(item, item.Quantity); err != nil {
+		return nil, false, err
+	}
+	if err := checkCostMethod(item); err != nil {
+		return nil, false, err
+	}
+	if err := checkTrackingMode(tx, item, trackedSerials); err != nil {
+		return nil, false, err
+	}
+	if item.SKU == "" {
+		if item.SKU, err = generatedSKU(tx, item.ID); err != nil {
+			return nil, false, err
+		}
+	}
+	item.UpdatedAt = time.Now()
+	// The quantity goes through adjustQuantity below, which keeps reserved
+	// stock and concurrent withdrawals intact.
+	if err := tx.Omit(clause.Associations, "quantity", "reserved").Save(item).Error; err != nil {
+		return nil, false, err
+	}
+	if in.Barcodes != nil {
+		if err := replaceBarcodes(tx, item, *in.Barcodes); err != nil {
+			return nil, false, err
+		}
+	}
+	if item.TrackLots != trackedLots {
+		if err := setLotTracking(tx, item); err != nil {
+			return nil, false, err
+		}
+	}
+	if delta == 0 {
+		return item, wasLow, nil
+	}
+	loc, err := quantityLocation(tx, item.ID, in.StockLocationID)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := adjustQuantity(tx, item.ID, delta, nil, item); err != nil {
+		return nil, false, err
+	}
+	m := &models.StockMovement{
+		Delta:      delta,
+		Reason:     reason,
+		Comment:    item.Comment,
+		Reference:  reference,
+		LocationID: &loc,
+	}
+	err = recordMovement(tx, item, withUnitCost(m, in.UnitCost))
+	return item, wasLow, err
+}
+
+// WithdrawQuantity decreases item quantity by delta at a location (0 for the
+// default one) and records the withdrawal. A non-empty comment also
+// replaces the item comment, as before. Only available stock can be
+// withdrawn, unless reservationID (0 for none) names a reservation of the
+// item: then its held stock is used first and the reservation shrinks.
+func (s *DatabaseService) WithdrawQuantity(id uint, locationID uint, delta models.Quantity, comment string, reservationID uint) (*models.Item, error) {
+	var item models.Item
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		loc, err := resolveLocation(tx, locationID)
+		if err != nil {
+			return err
+		}
+		var reference string
+		if reservationID != 0 {
+			r, err := consumeReservation(tx, reservationID, id, loc, delta)
+			if err != nil {
+				return err
+			}
+			reference = r.Holder
+		}
+		extra := map[string]interface{}{}
+		if comment != "" {
+			extra["comment"] = comment
+		}
+		if err := adjustQuantity(tx, id, -delta, extra, &item); err != nil {
+			return err
+		}
+		return recordMovement(tx, &item, &models.StockMovement{
+			Delta:      -delta,
+			Reason:     models.MovementWithdraw,
+			Comment:    comment,
+			Reference:  reference,
+			LocationID: &loc,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.notifyLowStock(&item, models.IsLowStock(item.Quantity+delta, item.MinStock))
+	return &item, nil
+}
+
+// ReceiveQuantity increases item quantity by delta at a location (0 for the
+// default one) and records the receipt together with its supplier /
+// document reference and unit cost (nil for the item's current average cost).
+func (s *DatabaseService) ReceiveQuantity(id uint, locationID uint, delta models.Quantity, comment string, reference string, unitCost *models.Money) (*models.Item, error) {
+	var item models.Item
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		loc, err := resolveLocation(tx, locationID)
+		if err != nil {
+			return err
+		}
+		if err := adjustQuantity(tx, id, delta, nil, &item); err != nil {
+			return err
+		}
+		return recordMovement(tx, &item, withUnitCost(&models.StockMovement{
+			Delta:      delta,
+			Reason:     models.MovementReceive,
+			Comment:    comment,
+			Reference:  reference,
+			LocationID: &loc,
+		}, unitCost))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// DeleteItem soft-deletes an item. Its movements stay in the ledger.
+func (s *DatabaseService) DeleteItem(id uint) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		var item models.Item
+		if err := tx.First(&item, id).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&item).Error; err != nil {
+			return err
+		}
+		return recordMovement(tx, &item, &models.StockMovement{Reason: models.MovementDelete})
+	})
+}
+
+// RestoreItem undoes a soft delete.
+func (s *DatabaseService) RestoreItem(id uint) (*models.Item, error) {
+	var item models.Item
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("deleted_at IS NOT NULL").First(&item, id).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(&item).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		item.DeletedAt = gorm.DeletedAt{}
+		return recordMovement(tx, &item, &models.StockMovement{Reason: models.MovementRestore})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// ListDeletedItems returns soft-deleted items, most recently deleted first.
+func (s *DatabaseService) ListDeletedItems() ([]models.Item, error) {
+	var items []models.Item
+	err := s.DB.Unscoped().
+		Where("deleted_at IS NOT NULL").
+		Order("deleted_at desc").
+		Find(&items).Error
+	return items, err
+}
+
+// ListItemMovements returns the ledger of a single item, newest first.
+func (s *DatabaseService) ListItemMovements(itemID uint) ([]models.StockMovement, error) {
+	var movements []models.StockMovement
+	err := s.DB.Where("item_id = ?", itemID).
+		Order("created_at desc, id desc").
+		Find(&movements).Error
+	return movements, err
+}
+
+// ListMovements returns the warehouse-wide ledger, newest first.
+// limit <= 0 returns every movement.
+func (s *DatabaseService) ListMovements(limit int) ([]models.StockMovement, error) {
+	var movements []models.StockMovement
+	q := s.DB.Preload("Item", unscoped).Order("created_at desc, id desc")
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	err := q.Find(&movements).Error
+	return movements, err
+}
+
+// ListMovementsByReference returns every movement booked against a document reference.
+func (s *DatabaseService) ListMovementsByReference(reference string) ([]models.StockMovement, error) {
+	var movements []models.StockMovement
+	err := s.DB.Preload("Item", unscoped).
+		Where("reference = ?", reference).
+		Order("created_at asc, id asc").
+		Find(&movements).Error
+	return movements, err
+}
+
+// unscoped is a Preload condition that includes soft-deleted rows,
+// so movements of deleted items still show which item they belong to.
+func unscoped(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
+
+// adjustQuantity adds delta to the stored quantity with a single conditional
+// UPDATE, so concurrent callers never lose an update or overdraw stock:
+// a negative delta only applies while quantity - reserved >= -delta, after
+// expired reservations of the item are released. Reserved stock is thus
+// never taken by accident; a caller withdrawing stock held for it releases
+// its own reservation first in the same transaction (see consumeReservation
+// and releaseRequest). extra holds additional columns to set in the same
+// statement. delta must fit the item's precision. On success item is
+// reloaded with the resulting row.
+func adjustQuantity(tx *gorm.DB, id uint, delta models.Quantity, extra map[string]interface{}, item *models.Item) error {
+	return adjustStock(tx, id, delta, extra, item, true)
+}
+
+// adjustCountedQuantity applies a counted variance like adjustQuantity but
+// ignores reservations: stock found missing is gone whether it was held for
+// someone or not, so only the quantity must stay non-negative. Reservations
+// left exceeding the stock are trimmed with trimReservations.
+func adjustCountedQuantity(tx *gorm.DB, id uint, delta models.Quantity, item *models.Item) error {
+	return adjustStock(tx, id, delta, nil, item, false)
+}
+
+// adjustStock implements adjustQuantity; keepReserved guards reserved stock.
+func adjustStock(tx *gorm.DB, id uint, delta models.Quantity, extra map[string]interface{}, item *models.Item, keepReserved bool) error {
+	if err := checkDeltaPrecision(tx, id, delta); err != nil {
+		return err
+	}
+	if delta < 0 && keepReserved {
+		if _, err := expireReservations(tx, time.Now(), id); err != nil {
+			return err
+		}
+	}
+	updates := map[string]interface{}{
+		"quantity":   gorm.Expr("quantity + ?", delta),
+		"updated_at": time.Now(),
+	}
+	for k, v := range extra {
+		updates[k] = v
+	}
+	q := tx.Model(&models.Item{}).Where("id = ?", id)
+	switch {
+	case delta < 0 && keepReserved:
+		q = q.Where("quantity - reserved >= ?", -delta)
+	case delta < 0:
+		q = q.Where("quantity >= ?", -delta)
+	}
+	res := q.Updates(updates)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		// Either the item does not exist or there is not enough stock.
+		if err := tx.First(item, id).Error; err != nil {
+			return err
+		}
+		if item.Quantity >= -delta {
+			return fmt.Errorf("%w: %s %s reserved", ErrInsufficientQuantity, item.Reserved, item.Unit)
+		}
+		return ErrInsufficientQuantity
+	}
+	return tx.Preload("Barcodes").First(item, id).Error
+}
+
+// checkDeltaPrecision rejects deltas with more decimals than the item's unit allows.
+func checkDeltaPrecision(tx *gorm.DB, id uint, delta models.Quantity) error {
+	var item models.Item
+	if err := tx.Select("id", "unit", "precision").First(&item, id).Error; err != nil {
+		return err
+	}
+	return checkPrecision(&item, delta)
+}
+
+// recordMovement appends a ledger entry for item; item must already hold the
+// resulting quantity. ItemID and Balance of m are filled in here. A non-zero
+// delta is also applied to the item's balance at m.LocationID, which is
+// required then, and for lot-tracked items to m.LotID.
+// Without a lot, receipts go into a lot without number or expiry and
+// withdrawals are split first-expiry-first-out into one entry per lot.
+// Each entry is valued against the item's cost layers, see valueMovement.
+// Stocktake entries may take stock reserved at the location, like
+// adjustCountedQuantity.
+func recordMovement(tx *gorm.DB, item *models.Item, m *models.StockMovement) error {
+	m.ItemID = item.ID
+	m.Balance = item.Quantity
+	if m.Delta != 0 {
+		if m.LocationID == nil {
+			return fmt.Errorf("stock movement of %s has no location", item.Name)
+		}
+		if item.TrackSerials && m.SerialID == nil {
+			return ErrSerialTracked
+		}
+		if item.TrackLots && m.LotID == nil {
+			if m.Delta < 0 {
+				return recordLotWithdrawal(tx, item, m)
+			}
+			lot, err := findOrCreateLot(tx, item.ID, *m.LocationID, models.LotInput{})
+			if err != nil {
+				return err
+			}
+			m.LotID = &lot.ID
+		}
+		keepReserved := m.Reason != models.MovementStocktake
+		if err := adjustBalance(tx, item.ID, *m.LocationID, m.Delta, keepReserved); err != nil {
+			return err
+		}
+		if m.LotID != nil {
+			if err := adjustLot(tx, *m.LotID, item.ID, *m.LocationID, m.Delta); err != nil {
+				return err
+			}
+		}
+		if err := valueMovement(tx, item, m); err != nil {
+			return err
+		}
+	}
+	return tx.Create(m).Error
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"goods_wails_app/models"
+)
+
+// newTestDB opens a migrated database in a temporary directory.
+func newTestDB(t *testing.T) *DatabaseService {
+	t.Helper()
+	db, err := NewDatabaseService(t.TempDir(), "test.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := db.Migrate(); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// checkLedger asserts that the item's stored quantity, the sum of its
+// per-location balances and the sum of its movements agree, and that no
+// movement left the item below zero.
+func checkLedger(t *testing.T, db *DatabaseService, itemID uint, want models.Quantity) {
+	t.Helper()
+	var item models.Item
+	if err := db.DB.First(&item, itemID).Error; err != nil {
+		t.Fatal(err)
+	}
+	var balances, deltas models.Quantity
+	if err := db.DB.Model(&models.StockBalance{}).Where("item_id = ?", itemID).
+		Select("COALESCE(SUM(quantity), 0)").Scan(&balances).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.DB.Model(&models.StockMovement{}).Where("item_id = ?", itemID).
+		Select("COALESCE(SUM(delta), 0)").Scan(&deltas).Error; err != nil {
+		t.Fatal(err)
+	}
+	if item.Quantity != want || balances != want || deltas != want {
+		t.Fatalf("quantity %s, balances %s, movements %s; want %s", item.Quantity, balances, deltas, want)
+	}
+	var negative int64
+	if err := db.DB.Model(&models.StockMovement{}).Where("item_id = ? AND balance < 0", itemID).
+		Count(&negative).Error; err != nil {
+		t.Fatal(err)
+	}
+	if negative > 0 {
+		t.Fatalf("%d movements left the stock negative", negative)
+	}
+}
+
+func TestConcurrentWithdrawals(t *testing.T) {
+	db := newTestDB(t)
+	start, delta := models.Units(50), models.Units(3)
+	item, err := db.CreateItem(models.ItemInput{Name: "Болт М8", Quantity: start})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const workers = 40
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := db.WithdrawQuantity(item.ID, 0, delta, "", 0)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, ErrInsufficientQuantity):
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if want := int(start / delta); succeeded != want {
+		t.Fatalf("%d withdrawals succeeded, want %d", succeeded, want)
+	}
+	checkLedger(t, db, item.ID, start-models.Quantity(succeeded)*delta)
+}
+
+func TestConcurrentWithdrawalsAndReceipts(t *testing.T) {
+	db := newTestDB(t)
+	start, delta := models.Units(10), models.Units(2)
+	item, err := db.CreateItem(models.ItemInput{Name: "Шайба 8", Quantity: start})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const workers = 30
+	var mu sync.Mutex
+	want := start
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := db.WithdrawQuantity(item.ID, 0, delta, "", 0)
+			if err != nil && !errors.Is(err, ErrInsufficientQuantity) {
+				t.Errorf("withdraw: %v", err)
+				return
+			}
+			if err == nil {
+				mu.Lock()
+				want -= delta
+				mu.Unlock()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := db.ReceiveQuantity(item.ID, 0, delta, "", "", nil); err != nil {
+				t.Errorf("receive: %v", err)
+				return
+			}
+			mu.Lock()
+			want += delta
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	if t.Failed() {
+		return
+	}
+	checkLedger(t, db, item.ID, want)
+}
+
+func TestGeneratedSKUSkipsTakenOnes(t *testing.T) {
+	db := newTestDB(t)
+	typed := "SKU-000002"
+	if _, err := db.CreateItem(models.ItemInput{Name: "Болт", SKU: &typed}); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"SKU-000003", "SKU-000004"} {
+		item, err := db.CreateItem(models.ItemInput{Name: "Гайка"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if item.SKU != want {
+			t.Fatalf("item %d got SKU %s, want %s", item.ID, item.SKU, want)
+		}
+	}
+}
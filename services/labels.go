@@ -0,0 +1,403 @@
+package services
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"goods_wails_app/models"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/ean"
+	"github.com/boombuler/barcode/qr"
+	"github.com/go-pdf/fpdf"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// labelDPI is the resolution of PNG labels, enough for label printers.
+const labelDPI = 300
+
+// labelPadding is the blank border inside every label, in mm.
+const labelPadding = 2.0
+
+// Smallest label GenerateLabels accepts, in mm.
+const (
+	minLabelWidth  = 15.0
+	minLabelHeight = 10.0
+)
+
+// labelFont is the Go font parsed once for drawing text on PNG labels.
+var labelFont = sync.OnceValues(func() (*opentype.Font, error) {
+	return opentype.Parse(goregular.TTF)
+})
+
+// label is one label to render.
+type label struct {
+	name string
+	code barcode.Barcode
+}
+
+// rect is an area of a label in mm, relative to its top-left corner.
+type rect struct{ x, y, w, h float64 }
+
+// labelLayout splits a label into the name line, the code and the text line.
+// Hidden lines have zero height.
+type labelLayout struct {
+	name, code, text rect
+	fontSize         float64 // pt
+}
+
+// GenerateLabels renders labels for the items, in the order given, to path
+// and returns the files written: one PDF with label sheets, or for PNG one
+// file per label, numbered when there are several.
+func (s *DatabaseService) GenerateLabels(itemIDs []uint, tpl models.LabelTemplate, path string) ([]string, error) {
+	tpl, err := normalizeLabelTemplate(tpl)
+	if err != nil {
+		return nil, err
+	}
+	if len(itemIDs) == 0 {
+		return nil, fmt.Errorf("no items selected")
+	}
+	var items []models.Item
+	if err := s.DB.Preload("Barcodes").Where("id IN ?", itemIDs).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]models.Item, len(items))
+	for _, it := range items {
+		byID[it.ID] = it
+	}
+	var labels []label
+	for _, id := range itemIDs {
+		it, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("item %d not found", id)
+		}
+		code, err := encodeLabel(it, tpl)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", it.Name, err)
+		}
+		for range tpl.Copies {
+			labels = append(labels, label{name: it.Name, code: code})
+		}
+	}
+	if tpl.Format == models.FormatPNG {
+		return writeLabelsPNG(path, labels, tpl)
+	}
+	if err := writeLabelsPDF(path, labels, tpl); err != nil {
+		return nil, err
+	}
+	return []string{path}, nil
+}
+
+// normalizeLabelTemplate fills in the defaults of tpl and checks that the
+// labels fit on the page.
+func normalizeLabelTemplate(tpl models.LabelTemplate) (models.LabelTemplate, error) {
+	tpl.Format = strings.ToLower(tpl.Format)
+	if tpl.Format == "" {
+		tpl.Format = models.FormatPDF
+	}
+	if tpl.Format != models.FormatPDF && tpl.Format != models.FormatPNG {
+		return tpl, fmt.Errorf("unsupported label format %q", tpl.Format)
+	}
+	if tpl.Symbology == "" {
+		tpl.Symbology = models.SymbologyCode128
+	}
+	switch tpl.Symbology {
+	case models.SymbologyCode128, models.SymbologyEAN13, models.SymbologyQR:
+	default:
+		return tpl, fmt.Errorf("unsupported symbology %q", tpl.Symbology)
+	}
+	if tpl.Source == "" {
+		tpl.Source = models.LabelSourceSKU
+	}
+	switch tpl.Source {
+	case models.LabelSourceSKU, models.LabelSourceBarcode, models.LabelSourceID:
+	default:
+		return tpl, fmt.Errorf("unknown label source %q", tpl.Source)
+	}
+	if tpl.PageWidth <= 0 || tpl.PageHeight <= 0 {
+		tpl.PageWidth, tpl.PageHeight = models.A4Width, models.A4Height
+	}
+	tpl.Columns = max(tpl.Columns, 1)
+	tpl.Rows = max(tpl.Rows, 1)
+	tpl.Copies = max(tpl.Copies, 1)
+	if tpl.MarginLeft < 0 || tpl.MarginTop < 0 || tpl.GapX < 0 || tpl.GapY < 0 {
+		return tpl, fmt.Errorf("margins and gaps must not be negative")
+	}
+	cols, rows := float64(tpl.Columns), float64(tpl.Rows)
+	if tpl.LabelWidth <= 0 {
+		tpl.LabelWidth = (tpl.PageWidth - 2*tpl.MarginLeft - tpl.GapX*(cols-1)) / cols
+	}
+	if tpl.LabelHeight <= 0 {
+		tpl.LabelHeight = (tpl.PageHeight - 2*tpl.MarginTop - tpl.GapY*(rows-1)) / rows
+	}
+	if tpl.LabelWidth < minLabelWidth || tpl.LabelHeight < minLabelHeight {
+		return tpl, fmt.Errorf("labels must be at least %gx%g mm", minLabelWidth, minLabelHeight)
+	}
+	gridW := cols*tpl.LabelWidth + tpl.GapX*(cols-1)
+	gridH := rows*tpl.LabelHeight + tpl.GapY*(rows-1)
+	if tpl.MarginLeft == 0 {
+		tpl.MarginLeft = (tpl.PageWidth - gridW) / 2
+	}
+	if tpl.MarginTop == 0 {
+		tpl.MarginTop = (tpl.PageHeight - gridH) / 2
+	}
+	// A hundredth of a millimetre covers rounding in the label sizes.
+	if tpl.Format == models.FormatPDF && (tpl.MarginLeft+gridW > tpl.PageWidth+0.01 || tpl.MarginTop+gridH > tpl.PageHeight+0.01 || tpl.MarginLeft < 0 || tpl.MarginTop < 0) {
+		return tpl, fmt.Errorf("%dx%d labels of %gx%g mm do not fit on a %gx%g mm page",
+			tpl.Columns, tpl.Rows, tpl.LabelWidth, tpl.LabelHeight, tpl.PageWidth, tpl.PageHeight)
+	}
+	return tpl, nil
+}
+
+// encodeLabel builds the code of an item's label.
+func encodeLabel(it models.Item, tpl models.LabelTemplate) (barcode.Barcode, error) {
+	value := labelValue(it, tpl)
+	switch tpl.Symbology {
+	case models.SymbologyEAN13:
+		if tpl.Source != models.LabelSourceID {
+			// Prefer a registered barcode that is a valid EAN-13.
+			for _, b := range it.Barcodes {
+				if code, err := encodeEAN13(b.Code); err == nil {
+					return code, nil
+				}
+			}
+		}
+		return encodeEAN13(value)
+	case models.SymbologyQR:
+		return qr.Encode(value, qr.M, qr.Auto)
+	default:
+		code, err := code128.Encode(value)
+		if err != nil {
+			return nil, fmt.Errorf("%q cannot be encoded as Code 128", value)
+		}
+		return code, nil
+	}
+}
+
+// labelValue returns what the label code of an item encodes.
+func labelValue(it models.Item, tpl models.LabelTemplate) string {
+	id := strconv.FormatUint(uint64(it.ID), 10)
+	switch tpl.Source {
+	case models.LabelSourceID:
+		return id
+	case models.LabelSourceBarcode:
+		if len(it.Barcodes) > 0 {
+			return it.Barcodes[0].Code
+		}
+	}
+	if it.SKU != "" {
+		return it.SKU
+	}
+	return id
+}
+
+// encodeEAN13 accepts 12 digits, adding the check digit, or 13 digits with a
+// correct one.
+func encodeEAN13(value string) (barcode.Barcode, error) {
+	if len(value) != 12 && len(value) != 13 || strings.Trim(value, "0123456789") != "" {
+		return nil, fmt.Errorf("%q is not an EAN-13 number", value)
+	}
+	code, err := ean.Encode(value)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not an EAN-13 number: %w", value, err)
+	}
+	return code, nil
+}
+
+// layoutLabel places the parts of a label of w x h mm.
+func layoutLabel(w, h float64, tpl models.LabelTemplate) labelLayout {
+	inner := rect{labelPadding, labelPadding, w - 2*labelPadding, h - 2*labelPadding}
+	l := labelLayout{fontSize: math.Min(9, math.Max(5, inner.h/3.5))}
+	line := l.fontSize * 25.4 / 72 * 1.25
+	top, bottom := inner.y, inner.y+inner.h
+	if tpl.ShowName {
+		l.name = rect{inner.x, top, inner.w, line}
+		top += line + 0.5
+	}
+	if tpl.ShowText {
+		l.text = rect{inner.x, bottom - line, inner.w, line}
+		bottom -= line
+	}
+	l.code = rect{inner.x, top, inner.w, bottom - top}
+	return l
+}
+
+// codeGeometry fits code into r with its quiet zone and returns the top-left
+// corner of the first module and the size of one module, in r's units.
+// Linear codes fill the height; 2D codes stay square.
+func codeGeometry(code barcode.Barcode, r rect) (x, y, mw, mh float64) {
+	b := code.Bounds()
+	if code.Metadata().Dimensions == 2 {
+		const quiet = 4
+		side := math.Min(r.w, r.h)
+		m := side / float64(b.Dx()+2*quiet)
+		return r.x + (r.w-side)/2 + quiet*m, r.y + (r.h-side)/2 + quiet*m, m, m
+	}
+	const quiet = 10
+	m := r.w / float64(b.Dx()+2*quiet)
+	return r.x + quiet*m, r.y, m, r.h / float64(b.Dy())
+}
+
+// darkRuns calls fn for every horizontal run of n dark modules starting at
+// module (x, y).
+func darkRuns(code barcode.Barcode, fn func(x, y, n int)) {
+	b := code.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		start := -1
+		for x := b.Min.X; x <= b.Max.X; x++ {
+			dark := false
+			if x < b.Max.X {
+				c := color.GrayModel.Convert(code.At(x, y)).(color.Gray)
+				dark = c.Y < 128
+			}
+			switch {
+			case dark && start < 0:
+				start = x
+			case !dark && start >= 0:
+				fn(start-b.Min.X, y-b.Min.Y, x-start)
+				start = -1
+			}
+		}
+	}
+}
+
+// writeLabelsPDF lays the labels out on as many sheets as needed. Codes are
+// drawn as vector rectangles so they stay sharp at any printer resolution.
+func writeLabelsPDF(path string, labels []label, tpl models.LabelTemplate) error {
+	pdf := fpdf.NewCustom(&fpdf.InitType{
+		UnitStr: "mm",
+		Size:    fpdf.SizeType{Wd: tpl.PageWidth, Ht: tpl.PageHeight},
+	})
+	pdf.AddUTF8FontFromBytes("Go", "", goregular.TTF)
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.SetTitle("Этикетки", true)
+	pdf.SetFillColor(0, 0, 0)
+
+	layout := layoutLabel(tpl.LabelWidth, tpl.LabelHeight, tpl)
+	perPage := tpl.Columns * tpl.Rows
+	for i, l := range labels {
+		if i%perPage == 0 {
+			pdf.AddPage()
+		}
+		n := i % perPage
+		ox := tpl.MarginLeft + float64(n%tpl.Columns)*(tpl.LabelWidth+tpl.GapX)
+		oy := tpl.MarginTop + float64(n/tpl.Columns)*(tpl.LabelHeight+tpl.GapY)
+
+		pdf.SetFont("Go", "", layout.fontSize)
+		if layout.name.h > 0 {
+			pdf.SetXY(ox+layout.name.x, oy+layout.name.y)
+			pdf.CellFormat(layout.name.w, layout.name.h, fitText(pdf, l.name, layout.name.w), "", 0, "C", false, 0, "")
+		}
+		if layout.text.h > 0 {
+			pdf.SetXY(ox+layout.text.x, oy+layout.text.y)
+			pdf.CellFormat(layout.text.w, layout.text.h, fitText(pdf, l.code.Content(), layout.text.w), "", 0, "C", false, 0, "")
+		}
+		code := layout.code
+		code.x += ox
+		code.y += oy
+		x, y, mw, mh := codeGeometry(l.code, code)
+		darkRuns(l.code, func(cx, cy, n int) {
+			pdf.Rect(x+float64(cx)*mw, y+float64(cy)*mh, float64(n)*mw, mh, "F")
+		})
+	}
+	return pdf.OutputFileAndClose(path)
+}
+
+// writeLabelsPNG writes one image per label. With several labels the files
+// are numbered: labels-1.png, labels-2.png, ...
+func writeLabelsPNG(path string, labels []label, tpl models.LabelTemplate) ([]string, error) {
+	f, err := labelFont()
+	if err != nil {
+		return nil, err
+	}
+	layout := layoutLabel(tpl.LabelWidth, tpl.LabelHeight, tpl)
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{Size: layout.fontSize, DPI: labelDPI, Hinting: font.HintingFull})
+	if err != nil {
+		return nil, err
+	}
+	defer face.Close()
+
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	paths := make([]string, 0, len(labels))
+	for i, l := range labels {
+		img := renderLabelPNG(l, tpl, layout, face)
+		name := path
+		if len(labels) > 1 {
+			name = fmt.Sprintf("%s-%d%s", base, i+1, ext)
+		}
+		if err := writePNG(name, img); err != nil {
+			return paths, err
+		}
+		paths = append(paths, name)
+	}
+	return paths, nil
+}
+
+// renderLabelPNG draws a label at labelDPI.
+func renderLabelPNG(l label, tpl models.LabelTemplate, layout labelLayout, face font.Face) *image.Gray {
+	px := func(mm float64) int { return int(math.Round(mm * labelDPI / 25.4)) }
+	img := image.NewGray(image.Rect(0, 0, px(tpl.LabelWidth), px(tpl.LabelHeight)))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+
+	x, y, mw, mh := codeGeometry(l.code, layout.code)
+	darkRuns(l.code, func(cx, cy, n int) {
+		r := image.Rect(px(x+float64(cx)*mw), px(y+float64(cy)*mh), px(x+float64(cx+n)*mw), px(y+float64(cy+1)*mh))
+		draw.Draw(img, r, image.Black, image.Point{}, draw.Src)
+	})
+
+	text := func(s string, r rect) {
+		if r.h == 0 {
+			return
+		}
+		d := font.Drawer{Dst: img, Src: image.Black, Face: face}
+		s = fitTextFace(face, s, px(r.w))
+		width := d.MeasureString(s).Round()
+		m := face.Metrics()
+		baseline := px(r.y) + (px(r.h)+m.Ascent.Round()-m.Descent.Round())/2
+		d.Dot = fixed.P(px(r.x)+(px(r.w)-width)/2, baseline)
+		d.DrawString(s)
+	}
+	text(l.name, layout.name)
+	text(l.code.Content(), layout.text)
+	return img
+}
+
+// fitTextFace is fitText for PNG labels: it shortens s with an ellipsis
+// until it is at most width pixels wide.
+func fitTextFace(face font.Face, s string, width int) string {
+	if font.MeasureString(face, s).Round() <= width {
+		return s
+	}
+	r := []rune(s)
+	for len(r) > 0 && font.MeasureString(face, string(r)+"…").Round() > width {
+		r = r[:len(r)-1]
+	}
+	return string(r) + "…"
+}
+
+func writePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,318 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"goods_wails_app/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrDuplicateLocation is returned when another location already has the name.
+	ErrDuplicateLocation = errors.New("location name already in use")
+	// ErrLocationNotEmpty prevents deleting a location that still holds stock.
+	ErrLocationNotEmpty = errors.New("location still holds stock")
+	// ErrDefaultLocation prevents deleting or un-defaulting the default location;
+	// make another location the default first.
+	ErrDefaultLocation = errors.New("the default location cannot be removed")
+	// ErrLocationRequired is returned when a quantity change names no location
+	// and the item's stock is held at more than one.
+	ErrLocationRequired = errors.New("stock is held at several locations; choose one")
+)
+
+// ListLocations returns all locations ordered by name.
+func (s *DatabaseService) ListLocations() ([]models.Location, error) {
+	var locations []models.Location
+	err := s.DB.Order("name asc").Find(&locations).Error
+	return locations, err
+}
+
+// CreateLocation adds a location. Making it the default clears the flag on
+// the previous default.
+func (s *DatabaseService) CreateLocation(in models.LocationInput) (*models.Location, error) {
+	loc := &models.Location{}
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := applyLocationInput(tx, loc, in); err != nil {
+			return err
+		}
+		if err := tx.Create(loc).Error; err != nil {
+			return err
+		}
+		return setDefaultLocation(tx, loc)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return loc, nil
+}
+
+// UpdateLocation renames a location or makes it the default.
+func (s *DatabaseService) UpdateLocation(id uint, in models.LocationInput) (*models.Location, error) {
+	loc := &models.Location{}
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(loc, id).Error; err != nil {
+			return err
+		}
+		if loc.IsDefault && !in.IsDefault {
+			return ErrDefaultLocation
+		}
+		if err := applyLocationInput(tx, loc, in); err != nil {
+			return err
+		}
+		if err := tx.Save(loc).Error; err != nil {
+			return err
+		}
+		return setDefaultLocation(tx, loc)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return loc, nil
+}
+
+// DeleteLocation soft-deletes an empty location. Its movements stay in the ledger.
+func (s *DatabaseService) DeleteLocation(id uint) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		var loc models.Location
+		if err := tx.First(&loc, id).Error; err != nil {
+			return err
+		}
+		if loc.IsDefault {
+			return ErrDefaultLocation
+		}
+		var n int64
+		if err := tx.Model(&models.StockBalance{}).Where("location_id = ? AND quantity <> 0", id).Count(&n).Error; err != nil {
+			return err
+		}
+		if n > 0 {
+			return fmt.Errorf("%w: %s", ErrLocationNotEmpty, loc.Name)
+		}
+		return tx.Delete(&loc).Error
+	})
+}
+
+// ListLocationStock returns the non-zero balances held at a location, with
+// their items, ordered by item name.
+func (s *DatabaseService) ListLocationStock(locationID uint) ([]models.StockBalance, error) {
+	var balances []models.StockBalance
+	err := s.DB.Preload("Item.Barcodes").
+		Joins("JOIN items ON items.id = stock_balances.item_id AND items.deleted_at IS NULL").
+		Where("stock_balances.location_id = ? AND stock_balances.quantity <> 0", locationID).
+		Order("items.name asc").
+		Find(&balances).Error
+	return balances, err
+}
+
+// ListItemBalances returns where an item is kept: its non-zero balances per
+// location, ordered by location name.
+func (s *DatabaseService) ListItemBalances(itemID uint) ([]models.StockBalance, error) {
+	var balances []models.StockBalance
+	err := s.DB.Preload("Location", unscoped).
+		Joins("JOIN locations ON locations.id = stock_balances.location_id").
+		Where("stock_balances.item_id = ? AND stock_balances.quantity <> 0", itemID).
+		Order("locations.name asc").
+		Find(&balances).Error
+	return balances, err
+}
+
+// TransferStock moves qty of an item from one location to another. Both
+// legs are booked as transfer movements in one transaction; the item total
+// does not change. Location 0 means the default location.
+func (s *DatabaseService) TransferStock(itemID uint, from uint, to uint, qty models.Quantity, comment string) (*models.Item, error) {
+	if qty <= 0 {
+		return nil, fmt.Errorf("quantity must be positive")
+	}
+	var item models.Item
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if from, err = resolveLocation(tx, from); err != nil {
+			return err
+		}
+		if to, err = resolveLocation(tx, to); err != nil {
+			return err
+		}
+		if from == to {
+			return fmt.Errorf("source and target location are the same")
+		}
+		if err := checkDeltaPrecision(tx, itemID, qty); err != nil {
+			return err
+		}
+		if err := tx.First(&item, itemID).Error; err != nil {
+			return err
+		}
+		if item.TrackLots {
+			return transferLots(tx, &item, from, to, qty, comment)
+		}
+		err = recordMovement(tx, &item, &models.StockMovement{
+			Delta:      -qty,
+			Reason:     models.MovementTransfer,
+			Comment:    comment,
+			LocationID: &from,
+		})
+		if err != nil {
+			return err
+		}
+		return recordMovement(tx, &item, &models.StockMovement{
+			Delta:      qty,
+			Reason:     models.MovementTransfer,
+			Comment:    comment,
+			LocationID: &to,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.DB.Preload("Barcodes").Preload("Balances", "quantity <> 0").First(&item, itemID).Error; err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// transferLots moves qty of a lot-tracked item first-expiry-first-out,
+// expired lots included, recreating each lot taken at the target location
+// with the same number and dates.
+func transferLots(tx *gorm.DB, item *models.Item, from uint, to uint, qty models.Quantity, comment string) error {
+	portions, err := allocateLots(tx, item.ID, from, qty, true)
+	if err != nil {
+		return err
+	}
+	for _, p := range portions {
+		target, err := findOrCreateLot(tx, item.ID, to, models.LotInput{
+			Number:     p.lot.Number,
+			ReceivedAt: &p.lot.ReceivedAt,
+			ExpiresAt:  p.lot.ExpiresAt,
+		})
+		if err != nil {
+			return err
+		}
+		err = recordMovement(tx, item, &models.StockMovement{
+			Delta:      -p.qty,
+			Reason:     models.MovementTransfer,
+			Comment:    comment,
+			LocationID: &from,
+			LotID:      &p.lot.ID,
+		})
+		if err != nil {
+			return err
+		}
+		err = recordMovement(tx, item, &models.StockMovement{
+			Delta:      p.qty,
+			Reason:     models.MovementTransfer,
+			Comment:    comment,
+			LocationID: &to,
+			LotID:      &target.ID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveLocation returns id if it names an existing location, or the
+// default location's id when id is 0.
+func resolveLocation(tx *gorm.DB, id uint) (uint, error) {
+	var loc models.Location
+	q := tx.Select("id")
+	if id == 0 {
+		q = q.Where("is_default = ?", true)
+	} else {
+		q = q.Where("id = ?", id)
+	}
+	if err := q.First(&loc).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, fmt.Errorf("location %d not found", id)
+		}
+		return 0, err
+	}
+	return loc.ID, nil
+}
+
+// quantityLocation picks the location a change of an item's total quantity
+// is booked at: id when given, otherwise the only location holding the
+// item's stock, or the default location while it has stock nowhere.
+func quantityLocation(tx *gorm.DB, itemID uint, id uint) (uint, error) {
+	if id != 0 {
+		return resolveLocation(tx, id)
+	}
+	var held []uint
+	err := tx.Model(&models.StockBalance{}).Where("item_id = ? AND quantity <> 0", itemID).
+		Limit(2).Pluck("location_id", &held).Error
+	if err != nil {
+		return 0, err
+	}
+	switch len(held) {
+	case 0:
+		return resolveLocation(tx, 0)
+	case 1:
+		return held[0], nil
+	}
+	return 0, ErrLocationRequired
+}
+
+// adjustBalance adds delta to an item's balance at a location. Like
+// adjustQuantity, a negative delta only applies while enough stock is there
+// that is, with keepReserved, not reserved at the location.
+func adjustBalance(tx *gorm.DB, itemID uint, locationID uint, delta models.Quantity, keepReserved bool) error {
+	if delta < 0 {
+		q := tx.Model(&models.StockBalance{}).Where("item_id = ? AND location_id = ?", itemID, locationID)
+		if keepReserved {
+			q = q.Where("quantity - ("+reservedAt+") >= ?", itemID, locationID, time.Now(), -delta)
+		} else {
+			q = q.Where("quantity >= ?", -delta)
+		}
+		res := q.Update("quantity", gorm.Expr("quantity + ?", delta))
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			var balance models.StockBalance
+			err := tx.Where("item_id = ? AND location_id = ?", itemID, locationID).Limit(1).Find(&balance).Error
+			if err != nil {
+				return err
+			}
+			if balance.Quantity >= -delta {
+				return fmt.Errorf("%w: stock at this location is reserved", ErrInsufficientQuantity)
+			}
+			return ErrInsufficientQuantity
+		}
+		return nil
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "item_id"}, {Name: "location_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"quantity": gorm.Expr("stock_balances.quantity + excluded.quantity")}),
+	}).Create(&models.StockBalance{ItemID: itemID, LocationID: locationID, Quantity: delta}).Error
+}
+
+// applyLocationInput validates in and copies it onto loc.
+func applyLocationInput(tx *gorm.DB, loc *models.Location, in models.LocationInput) error {
+	name := strings.TrimSpace(in.Name)
+	if name == "" {
+		return fmt.Errorf("location name is required")
+	}
+	// Deleted locations give up their name.
+	var n int64
+	if err := tx.Model(&models.Location{}).Where("name = ? AND id <> ?", name, loc.ID).Count(&n).Error; err != nil {
+		return err
+	}
+	if n > 0 {
+		return fmt.Errorf("%w: %s", ErrDuplicateLocation, name)
+	}
+	loc.Name = name
+	loc.Comment = strings.TrimSpace(in.Comment)
+	loc.IsDefault = in.IsDefault
+	return nil
+}
+
+// setDefaultLocation clears the default flag on every location but loc when loc is the default.
+func setDefaultLocation(tx *gorm.DB, loc *models.Location) error {
+	if !loc.IsDefault {
+		return nil
+	}
+	return tx.Model(&models.Location{}).Where("id <> ? AND is_default = ?", loc.ID, true).Update("is_default", false).Error
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"goods_wails_app/models"
+)
+
+func TestReuseNameOfDeletedLocation(t *testing.T) {
+	db := newTestDB(t)
+	in := models.LocationInput{Name: "Склад 2"}
+	loc, err := db.CreateLocation(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.CreateLocation(in); !errors.Is(err, ErrDuplicateLocation) {
+		t.Fatalf("second live location: %v, want ErrDuplicateLocation", err)
+	}
+	if err := db.DeleteLocation(loc.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.CreateLocation(in); err != nil {
+		t.Fatalf("reusing the name of a deleted location: %v", err)
+	}
+}
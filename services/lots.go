@@ -0,0 +1,276 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"goods_wails_app/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrNotLotTracked is returned by lot operations on items without TrackLots.
+var ErrNotLotTracked = errors.New("item is not lot-tracked")
+
+// ListItemLots returns the lots of an item that still hold stock, in the
+// order withdrawals consume them.
+func (s *DatabaseService) ListItemLots(itemID uint) ([]models.Lot, error) {
+	var lots []models.Lot
+	err := s.DB.Preload("Location", unscoped).
+		Where("item_id = ? AND quantity > 0", itemID).
+		Scopes(fefoOrder).
+		Find(&lots).Error
+	return lots, err
+}
+
+// ListExpiring returns lots with stock that expire within days from now,
+// already expired ones included, soonest first.
+func (s *DatabaseService) ListExpiring(days int) ([]models.Lot, error) {
+	var lots []models.Lot
+	err := s.DB.Preload("Item").Preload("Location", unscoped).
+		Joins("JOIN items ON items.id = lots.item_id AND items.deleted_at IS NULL").
+		Where("lots.quantity > 0 AND lots.expires_at IS NOT NULL AND lots.expires_at <= ?", time.Now().AddDate(0, 0, days)).
+		Order("lots.expires_at asc, lots.id asc").
+		Find(&lots).Error
+	return lots, err
+}
+
+// ReceiveLot receives delta of a lot-tracked item into the lot described by
+// lot at a location (0 for the default one). A lot with the same number and
+// expiry already at the location is topped up instead of duplicated.
+// unitCost values the receipt; nil uses the item's current average cost.
+func (s *DatabaseService) ReceiveLot(itemID uint, locationID uint, delta models.Quantity, lot models.LotInput, comment string, reference string, unitCost *models.Money) (*models.Item, error) {
+	if delta <= 0 {
+		return nil, fmt.Errorf("delta must be positive")
+	}
+	var item models.Item
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		loc, err := resolveLocation(tx, locationID)
+		if err != nil {
+			return err
+		}
+		if err := tx.First(&item, itemID).Error; err != nil {
+			return err
+		}
+		if !item.TrackLots {
+			return ErrNotLotTracked
+		}
+		target, err := findOrCreateLot(tx, itemID, loc, lot)
+		if err != nil {
+			return err
+		}
+		if err := adjustQuantity(tx, itemID, delta, nil, &item); err != nil {
+			return err
+		}
+		return recordMovement(tx, &item, withUnitCost(&models.StockMovement{
+			Delta:      delta,
+			Reason:     models.MovementReceive,
+			Comment:    comment,
+			Reference:  reference,
+			LocationID: &loc,
+			LotID:      &target.ID,
+		}, unitCost))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// WithdrawFromLot withdraws delta from a specific lot, overriding the
+// first-expiry-first-out choice WithdrawQuantity makes. Expired lots can
+// only be withdrawn this way.
+func (s *DatabaseService) WithdrawFromLot(lotID uint, delta models.Quantity, comment string) (*models.Item, error) {
+	if delta <= 0 {
+		return nil, fmt.Errorf("delta must be positive")
+	}
+	var item models.Item
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var lot models.Lot
+		if err := tx.First(&lot, lotID).Error; err != nil {
+			return err
+		}
+		extra := map[string]interface{}{}
+		if comment != "" {
+			extra["comment"] = comment
+		}
+		if err := adjustQuantity(tx, lot.ItemID, -delta, extra, &item); err != nil {
+			return err
+		}
+		return recordMovement(tx, &item, &models.StockMovement{
+			Delta:      -delta,
+			Reason:     models.MovementWithdraw,
+			Comment:    comment,
+			LocationID: &lot.LocationID,
+			LotID:      &lot.ID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.notifyLowStock(&item, models.IsLowStock(item.Quantity+delta, item.MinStock))
+	return &item, nil
+}
+
+// fefoOrder sorts lots first-expiry-first-out; lots without expiry go last,
+// oldest receipt first.
+func fefoOrder(db *gorm.DB) *gorm.DB {
+	return db.Order("expires_at IS NULL, expires_at asc, received_at asc, id asc")
+}
+
+// lotPortion is the part of a lot taken by a withdrawal.
+type lotPortion struct {
+	lot models.Lot
+	qty models.Quantity
+}
+
+// allocateLots picks qty of an item at a location from its lots, first
+// expiry first. Expired lots are passed over unless withExpired is set.
+func allocateLots(tx *gorm.DB, itemID uint, locationID uint, qty models.Quantity, withExpired bool) ([]lotPortion, error) {
+	q := tx.Where("item_id = ? AND location_id = ? AND quantity > 0", itemID, locationID)
+	if !withExpired {
+		q = q.Where("expires_at IS NULL OR expires_at > ?", time.Now())
+	}
+	var lots []models.Lot
+	if err := q.Scopes(fefoOrder).Find(&lots).Error; err != nil {
+		return nil, err
+	}
+	var portions []lotPortion
+	for _, lot := range lots {
+		if qty == 0 {
+			break
+		}
+		take := min(lot.Quantity, qty)
+		portions = append(portions, lotPortion{lot: lot, qty: take})
+		qty -= take
+	}
+	if qty > 0 && !withExpired {
+		return nil, fmt.Errorf("%w: expired lots are not issued, withdraw them by lot", ErrInsufficientQuantity)
+	}
+	if qty > 0 {
+		return nil, ErrInsufficientQuantity
+	}
+	return portions, nil
+}
+
+// recordLotWithdrawal books a withdrawal from a lot-tracked item without a
+// chosen lot as one movement per lot consumed. Goods going out skip expired
+// lots; corrections of the count take stock from any lot.
+func recordLotWithdrawal(tx *gorm.DB, item *models.Item, m *models.StockMovement) error {
+	correction := m.Reason == models.MovementAdjust || m.Reason == models.MovementImport || m.Reason == models.MovementStocktake
+	portions, err := allocateLots(tx, item.ID, *m.LocationID, -m.Delta, correction)
+	if err != nil {
+		return err
+	}
+	remaining := -m.Delta
+	for _, p := range portions {
+		remaining -= p.qty
+		part := *m
+		part.Delta = -p.qty
+		part.LotID = &p.lot.ID
+		// Each entry shows the balance right after its own part.
+		at := *item
+		at.Quantity = item.Quantity + remaining
+		if err := recordMovement(tx, &at, &part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findOrCreateLot returns the lot of an item at a location matching in,
+// creating an empty one if there is none. Only the date of the expiry
+// counts.
+func findOrCreateLot(tx *gorm.DB, itemID uint, locationID uint, in models.LotInput) (*models.Lot, error) {
+	number := strings.TrimSpace(in.Number)
+	expires := expiryDate(in.ExpiresAt)
+	q := tx.Where("item_id = ? AND location_id = ? AND number = ?", itemID, locationID, number)
+	if expires != nil {
+		q = q.Where("expires_at = ?", *expires)
+	} else {
+		q = q.Where("expires_at IS NULL")
+	}
+	var lot models.Lot
+	err := q.Order("id asc").First(&lot).Error
+	if err == nil {
+		return &lot, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	lot = models.Lot{
+		ItemID:     itemID,
+		LocationID: locationID,
+		Number:     number,
+		ReceivedAt: time.Now(),
+		ExpiresAt:  expires,
+	}
+	if in.ReceivedAt != nil {
+		lot.ReceivedAt = *in.ReceivedAt
+	}
+	if err := tx.Create(&lot).Error; err != nil {
+		return nil, err
+	}
+	return &lot, nil
+}
+
+// adjustLot adds delta to a lot, which must belong to the item and location.
+// A negative delta only applies while the lot holds enough.
+func adjustLot(tx *gorm.DB, lotID uint, itemID uint, locationID uint, delta models.Quantity) error {
+	q := tx.Model(&models.Lot{}).Where("id = ? AND item_id = ? AND location_id = ?", lotID, itemID, locationID)
+	if delta < 0 {
+		q = q.Where("quantity >= ?", -delta)
+	}
+	res := q.Update("quantity", gorm.Expr("quantity + ?", delta))
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		var n int64
+		if err := tx.Model(&models.Lot{}).Where("id = ? AND item_id = ? AND location_id = ?", lotID, itemID, locationID).Count(&n).Error; err != nil {
+			return err
+		}
+		if n == 0 {
+			return fmt.Errorf("lot %d does not belong to the item at this location", lotID)
+		}
+		return ErrInsufficientQuantity
+	}
+	return nil
+}
+
+// expiryDate is midnight of the calendar date of t as given, in local time
+// like every stored time; nil stays nil.
+func expiryDate(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	y, m, d := t.Date()
+	date := time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+	return &date
+}
+
+// setLotTracking reconciles the lots of item after TrackLots changed. The
+// old lots are emptied; when tracking is turned on, the stock at each
+// location becomes a lot without number or expiry.
+func setLotTracking(tx *gorm.DB, item *models.Item) error {
+	if err := tx.Model(&models.Lot{}).Where("item_id = ?", item.ID).Update("quantity", 0).Error; err != nil {
+		return err
+	}
+	if !item.TrackLots {
+		return nil
+	}
+	var balances []models.StockBalance
+	if err := tx.Where("item_id = ? AND quantity > 0", item.ID).Find(&balances).Error; err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, b := range balances {
+		lot := models.Lot{ItemID: item.ID, LocationID: b.LocationID, ReceivedAt: now, Quantity: b.Quantity}
+		if err := tx.Create(&lot).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"goods_wails_app/models"
+)
+
+func TestReceiveLotMatchesExpiryDate(t *testing.T) {
+	db := newTestDB(t)
+	yes := true
+	item, err := db.CreateItem(models.ItemInput{Name: "Йогурт", TrackLots: &yes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	morning := time.Date(2030, 5, 20, 9, 30, 0, 0, time.Local)
+	utc := time.Date(2030, 5, 20, 0, 0, 0, 0, time.UTC)
+	for _, expires := range []time.Time{morning, morning.Add(8 * time.Hour), utc} {
+		lot := models.LotInput{Number: "B-7", ExpiresAt: &expires}
+		if _, err := db.ReceiveLot(item.ID, 0, models.Units(2), lot, "", "", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lots, err := db.ListItemLots(item.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lots) != 1 || lots[0].Quantity != models.Units(6) {
+		t.Fatalf("got %d lots, want one lot of 6: %+v", len(lots), lots)
+	}
+}
+
+func TestWithdrawSkipsExpiredLots(t *testing.T) {
+	db := newTestDB(t)
+	yes := true
+	item, err := db.CreateItem(models.ItemInput{Name: "Йогурт", TrackLots: &yes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired := time.Now().AddDate(0, 0, -3)
+	fresh := time.Now().AddDate(0, 1, 0)
+	for _, expires := range []*time.Time{&expired, &fresh} {
+		lot := models.LotInput{ExpiresAt: expires}
+		if _, err := db.ReceiveLot(item.ID, 0, models.Units(5), lot, "", "", nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lots, err := db.ListItemLots(item.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old, good := lots[0], lots[1]
+
+	if _, err := db.WithdrawQuantity(item.ID, 0, models.Units(3), "", 0); err != nil {
+		t.Fatal(err)
+	}
+	quantities := func() map[uint]models.Quantity {
+		lots, err := db.ListItemLots(item.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := map[uint]models.Quantity{}
+		for _, l := range lots {
+			got[l.ID] = l.Quantity
+		}
+		return got
+	}
+	if got := quantities(); got[old.ID] != models.Units(5) || got[good.ID] != models.Units(2) {
+		t.Fatalf("after withdrawal: expired lot %s, fresh lot %s", got[old.ID], got[good.ID])
+	}
+	if _, err := db.WithdrawQuantity(item.ID, 0, models.Units(3), "", 0); !errors.Is(err, ErrInsufficientQuantity) {
+		t.Fatalf("withdrawing into expired stock: %v, want ErrInsufficientQuantity", err)
+	}
+
+	// A correction of the count takes stock from expired lots as well.
+	if _, err := db.UpdateItem(item.ID, models.ItemInput{Name: "Йогурт", Quantity: models.Units(1)}); err != nil {
+		t.Fatal(err)
+	}
+	if got := quantities(); got[old.ID] != 0 || got[good.ID] != models.Units(1) {
+		t.Fatalf("after correction: expired lot %s, fresh lot %s", got[old.ID], got[good.ID])
+	}
+	checkLedger(t, db, item.ID, models.Units(1))
+}
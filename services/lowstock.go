@@ -0,0 +1,22 @@
+package services
+
+import "goods_wails_app/models"
+
+// ListLowStock returns items at or below their minimum stock, ordered by name.
+// Items without a minimum (MinStock = 0) are never listed.
+func (s *DatabaseService) ListLowStock() ([]models.Item, error) {
+	var items []models.Item
+	err := s.DB.Preload("Barcodes").
+		Where("min_stock > 0 AND quantity <= min_stock").
+		Order("name asc").
+		Find(&items).Error
+	return items, err
+}
+
+// notifyLowStock calls OnLowStock if item is low now but was not before the change.
+func (s *DatabaseService) notifyLowStock(item *models.Item, wasLow bool) {
+	if s.OnLowStock == nil || wasLow || !item.IsLowStock() {
+		return
+	}
+	s.OnLowStock(*item)
+}
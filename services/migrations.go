@@ -0,0 +1,556 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrSchemaTooNew is returned by Migrate when the database was written by a
+// newer build of the application than the one running.
+var ErrSchemaTooNew = errors.New("database schema is newer than this application")
+
+// Migration is a single numbered schema change. Up runs inside a transaction
+// together with the schema_migrations bookkeeping row.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *gorm.DB) error
+}
+
+// schemaMigration is a row of the schema_migrations table.
+type schemaMigration struct {
+	Version   int    `gorm:"primaryKey;autoIncrement:false"`
+	Name      string `gorm:"not null"`
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string { return "schema_migrations" }
+
+// migrations lists every schema change in apply order. Never edit or reorder
+// an entry that has shipped; append a new one instead. Migrations must not
+// reference the live models (they keep changing); use the frozen snapshots
+// in migrations_schema.go or plain SQL.
+var migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "items and stock movements",
+		Up: func(tx *gorm.DB) error {
+			// TestMigrationV1Schema pins the schema this creates.
+			return tx.AutoMigrate(&itemV1{}, &stockMovementV1{})
+		},
+	},
+	{
+		Version: 2,
+		Name:    "catalog fields and barcodes",
+		Up: func(tx *gorm.DB) error {
+			return execAll(tx,
+				`ALTER TABLE items ADD COLUMN sku text NOT NULL DEFAULT ''`,
+				`ALTER TABLE items ADD COLUMN unit text NOT NULL DEFAULT 'шт'`,
+				`ALTER TABLE items ADD COLUMN category text NOT NULL DEFAULT ''`,
+				`ALTER TABLE items ADD COLUMN location text NOT NULL DEFAULT ''`,
+				`UPDATE items SET sku = printf('SKU-%06d', id) WHERE sku = ''`,
+				`CREATE UNIQUE INDEX idx_items_sku ON items(sku)`,
+				`CREATE INDEX idx_items_category ON items(category)`,
+				`CREATE TABLE item_barcodes (
+					id integer PRIMARY KEY AUTOINCREMENT,
+					item_id integer NOT NULL,
+					code text NOT NULL,
+					CONSTRAINT fk_items_barcodes FOREIGN KEY (item_id) REFERENCES items(id)
+				)`,
+				`CREATE INDEX idx_item_barcodes_item_id ON item_barcodes(item_id)`,
+				`CREATE UNIQUE INDEX idx_item_barcodes_code ON item_barcodes(code)`,
+			)
+		},
+	},
+	{
+		Version: 3,
+		Name:    "fixed-point decimal quantities",
+		Up: func(tx *gorm.DB) error {
+			// Quantities become integer counts of 1/1000 units (models.QuantityScale).
+			return execAll(tx,
+				`UPDATE items SET quantity = quantity * 1000`,
+				`UPDATE stock_movements SET delta = delta * 1000, balance = balance * 1000`,
+				`ALTER TABLE items ADD COLUMN precision integer NOT NULL DEFAULT 0`,
+				`UPDATE items SET precision = CASE unicode_lower(trim(unit, '. '))
+					WHEN 'кг' THEN 3 WHEN 'т' THEN 3 WHEN 'л' THEN 3
+					WHEN 'м3' THEN 3 WHEN 'м³' THEN 3
+					WHEN 'м' THEN 2 WHEN 'м2' THEN 2 WHEN 'м²' THEN 2
+					WHEN 'см' THEN 1
+					ELSE 0 END`,
+			)
+		},
+	},
+	{
+		Version: 4,
+		Name:    "low-stock thresholds",
+		Up: func(tx *gorm.DB) error {
+			return execAll(tx,
+				`ALTER TABLE items ADD COLUMN min_stock integer NOT NULL DEFAULT 0`,
+				`ALTER TABLE items ADD COLUMN reorder_qty integer NOT NULL DEFAULT 0`,
+			)
+		},
+	},
+	{
+		Version: 5,
+		Name:    "user accounts and movement attribution",
+		Up: func(tx *gorm.DB) error {
+			return execAll(tx,
+				`CREATE TABLE users (
+					id integer PRIMARY KEY AUTOINCREMENT,
+					username text NOT NULL,
+					display_name text NOT NULL DEFAULT '',
+					password_hash text NOT NULL,
+					role text NOT NULL,
+					disabled numeric NOT NULL DEFAULT false,
+					last_login_at datetime,
+					created_at datetime,
+					updated_at datetime
+				)`,
+				`CREATE UNIQUE INDEX idx_users_username ON users(username)`,
+				`ALTER TABLE stock_movements ADD COLUMN user_id integer`,
+				`ALTER TABLE stock_movements ADD COLUMN user_name text NOT NULL DEFAULT ''`,
+				`CREATE INDEX idx_stock_movements_user_id ON stock_movements(user_id)`,
+			)
+		},
+	},
+	{
+		Version: 6,
+		Name:    "locations and per-location balances",
+		Up: func(tx *gorm.DB) error {
+			err := execAll(tx,
+				`CREATE TABLE locations (
+					id integer PRIMARY KEY AUTOINCREMENT,
+					name text NOT NULL,
+					comment text NOT NULL DEFAULT '',
+					is_default numeric NOT NULL DEFAULT false,
+					created_at datetime,
+					updated_at datetime,
+					deleted_at datetime
+				)`,
+				`CREATE UNIQUE INDEX idx_locations_name ON locations(name)`,
+				`CREATE INDEX idx_locations_deleted_at ON locations(deleted_at)`,
+				`CREATE TABLE stock_balances (
+					item_id integer NOT NULL,
+					location_id integer NOT NULL,
+					quantity integer NOT NULL DEFAULT 0,
+					PRIMARY KEY (item_id, location_id),
+					CONSTRAINT fk_items_balances FOREIGN KEY (item_id) REFERENCES items(id),
+					CONSTRAINT fk_stock_balances_location FOREIGN KEY (location_id) REFERENCES locations(id)
+				)`,
+				`CREATE INDEX idx_stock_balances_location_id ON stock_balances(location_id)`,
+				`ALTER TABLE stock_movements ADD COLUMN location_id integer`,
+				`CREATE INDEX idx_stock_movements_location_id ON stock_movements(location_id)`,
+			)
+			if err != nil {
+				return err
+			}
+			// Everything in stock so far is in the one storeroom there was.
+			now := time.Now()
+			err = tx.Exec(`INSERT INTO locations (id, name, is_default, created_at, updated_at) VALUES (1, 'Основной склад', true, ?, ?)`, now, now).Error
+			if err != nil {
+				return err
+			}
+			return execAll(tx,
+				`INSERT INTO stock_balances (item_id, location_id, quantity) SELECT id, 1, quantity FROM items WHERE quantity <> 0`,
+				`UPDATE stock_movements SET location_id = 1 WHERE delta <> 0`,
+			)
+		},
+	},
+	{
+		Version: 7,
+		Name:    "lots with expiry dates",
+		Up: func(tx *gorm.DB) error {
+			return execAll(tx,
+				`ALTER TABLE items ADD COLUMN track_lots numeric NOT NULL DEFAULT false`,
+				`CREATE TABLE lots (
+					id integer PRIMARY KEY AUTOINCREMENT,
+					item_id integer NOT NULL,
+					location_id integer NOT NULL,
+					number text NOT NULL DEFAULT '',
+					received_at datetime NOT NULL,
+					expires_at datetime,
+					quantity integer NOT NULL DEFAULT 0,
+					created_at datetime,
+					CONSTRAINT fk_lots_item FOREIGN KEY (item_id) REFERENCES items(id),
+					CONSTRAINT fk_lots_location FOREIGN KEY (location_id) REFERENCES locations(id)
+				)`,
+				`CREATE INDEX idx_lots_item_id ON lots(item_id)`,
+				`CREATE INDEX idx_lots_location_id ON lots(location_id)`,
+				`CREATE INDEX idx_lots_expires_at ON lots(expires_at)`,
+				`ALTER TABLE stock_movements ADD COLUMN lot_id integer`,
+				`CREATE INDEX idx_stock_movements_lot_id ON stock_movements(lot_id)`,
+			)
+		},
+	},
+	{
+		Version: 8,
+		Name:    "serial-tracked items",
+		Up: func(tx *gorm.DB) error {
+			return execAll(tx,
+				`ALTER TABLE items ADD COLUMN track_serials numeric NOT NULL DEFAULT false`,
+				`CREATE TABLE item_serials (
+					id integer PRIMARY KEY AUTOINCREMENT,
+					item_id integer NOT NULL,
+					serial text NOT NULL,
+					status text NOT NULL DEFAULT 'in_stock',
+					location_id integer NOT NULL,
+					issued_to text NOT NULL DEFAULT '',
+					comment text NOT NULL DEFAULT '',
+					created_at datetime,
+					updated_at datetime,
+					CONSTRAINT fk_item_serials_item FOREIGN KEY (item_id) REFERENCES items(id),
+					CONSTRAINT fk_item_serials_location FOREIGN KEY (location_id) REFERENCES locations(id)
+				)`,
+				`CREATE UNIQUE INDEX idx_item_serials_serial ON item_serials(serial)`,
+				`CREATE INDEX idx_item_serials_item_id ON item_serials(item_id)`,
+				`CREATE INDEX idx_item_serials_status ON item_serials(status)`,
+				`ALTER TABLE stock_movements ADD COLUMN serial_id integer`,
+				`CREATE INDEX idx_stock_movements_serial_id ON stock_movements(serial_id)`,
+			)
+		},
+	},
+	{
+		Version: 9,
+		Name:    "scan sessions",
+		Up: func(tx *gorm.DB) error {
+			return execAll(tx,
+				`CREATE TABLE scan_sessions (
+					id integer PRIMARY KEY AUTOINCREMENT,
+					kind text NOT NULL,
+					status text NOT NULL DEFAULT 'open',
+					location_id integer NOT NULL,
+					comment text NOT NULL DEFAULT '',
+					reference text NOT NULL DEFAULT '',
+					user_id integer,
+					user_name text NOT NULL DEFAULT '',
+					created_at datetime,
+					updated_at datetime,
+					closed_at datetime,
+					CONSTRAINT fk_scan_sessions_location FOREIGN KEY (location_id) REFERENCES locations(id)
+				)`,
+				`CREATE INDEX idx_scan_sessions_status ON scan_sessions(status)`,
+				`CREATE TABLE scan_lines (
+					id integer PRIMARY KEY AUTOINCREMENT,
+					session_id integer NOT NULL,
+					item_id integer NOT NULL,
+					serial_id integer,
+					code text NOT NULL DEFAULT '',
+					quantity integer NOT NULL,
+					created_at datetime,
+					updated_at datetime,
+					CONSTRAINT fk_scan_sessions_lines FOREIGN KEY (session_id) REFERENCES scan_sessions(id),
+					CONSTRAINT fk_scan_lines_item FOREIGN KEY (item_id) REFERENCES items(id),
+					CONSTRAINT fk_scan_lines_serial FOREIGN KEY (serial_id) REFERENCES item_serials(id)
+				)`,
+				`CREATE INDEX idx_scan_lines_session_id ON scan_lines(session_id)`,
+			)
+		},
+	},
+	{
+		Version: 10,
+		Name:    "stocktakes",
+		Up: func(tx *gorm.DB) error {
+			return execAll(tx,
+				`CREATE TABLE stocktakes (
+					id integer PRIMARY KEY AUTOINCREMENT,
+					status text NOT NULL DEFAULT 'open',
+					location_id integer NOT NULL,
+					category text NOT NULL DEFAULT '',
+					comment text NOT NULL DEFAULT '',
+					reference text NOT NULL DEFAULT '',
+					user_id integer,
+					user_name text NOT NULL DEFAULT '',
+					created_at datetime,
+					updated_at datetime,
+					closed_at datetime,
+					CONSTRAINT fk_stocktakes_location FOREIGN KEY (location_id) REFERENCES locations(id)
+				)`,
+				`CREATE INDEX idx_stocktakes_status ON stocktakes(status)`,
+				`CREATE TABLE stocktake_lines (
+					id integer PRIMARY KEY AUTOINCREMENT,
+					stocktake_id integer NOT NULL,
+					item_id integer NOT NULL,
+					expected integer NOT NULL,
+					counted integer,
+					variance integer NOT NULL DEFAULT 0,
+					created_at datetime,
+					updated_at datetime,
+					CONSTRAINT fk_stocktakes_lines FOREIGN KEY (stocktake_id) REFERENCES stocktakes(id),
+					CONSTRAINT fk_stocktake_lines_item FOREIGN KEY (item_id) REFERENCES items(id)
+				)`,
+				`CREATE UNIQUE INDEX idx_stocktake_lines_item ON stocktake_lines(stocktake_id, item_id)`,
+			)
+		},
+	},
+	{
+		Version: 11,
+		Name:    "suppliers and purchase orders",
+		Up: func(tx *gorm.DB) error {
+			return execAll(tx,
+				`CREATE TABLE suppliers (
+					id integer PRIMARY KEY AUTOINCREMENT,
+					name text NOT NULL,
+					inn text NOT NULL DEFAULT '',
+					contact text NOT NULL DEFAULT '',
+					phone text NOT NULL DEFAULT '',
+					email text NOT NULL DEFAULT '',
+					comment text NOT NULL DEFAULT '',
+					created_at datetime,
+					updated_at datetime,
+					deleted_at datetime
+				)`,
+				`CREATE UNIQUE INDEX idx_suppliers_name ON suppliers(name)`,
+				`CREATE INDEX idx_suppliers_deleted_at ON suppliers(deleted_at)`,
+				`CREATE TABLE purchase_orders (
+					id integer PRIMARY KEY AUTOINCREMENT,
+					number text NOT NULL,
+					supplier_id integer NOT NULL,
+					status text NOT NULL DEFAULT 'draft',
+					location_id integer NOT NULL,
+					comment text NOT NULL DEFAULT '',
+					expected_at datetime,
+					user_id integer,
+					user_name text NOT NULL DEFAULT '',
+					created_at datetime,
+					updated_at datetime,
+					sent_at datetime,
+					closed_at datetime,
+					CONSTRAINT fk_purchase_orders_supplier FOREIGN KEY (supplier_id) REFERENCES suppliers(id),
+					CONSTRAINT fk_purchase_orders_location FOREIGN KEY (location_id) REFERENCES locations(id)
+				)`,
+				`CREATE UNIQUE INDEX idx_purchase_orders_number ON purchase_orders(number)`,
+				`CREATE INDEX idx_purchase_orders_supplier_id ON purchase_orders(supplier_id)`,
+				`CREATE INDEX idx_purchase_orders_status ON purchase_orders(status)`,
+				`CREATE TABLE purchase_order_lines (
+					id integer PRIMARY KEY AUTOINCREMENT,
+					order_id integer NOT NULL,
+					item_id integer NOT NULL,
+					ordered integer NOT NULL,
+					received integer NOT NULL DEFAULT 0,
+					price integer NOT NULL DEFAULT 0,
+					CONSTRAINT fk_purchase_orders_lines FOREIGN KEY (order_id) REFERENCES purchase_orders(id),
+					CONSTRAINT fk_purchase_order_lines_item FOREIGN KEY (item_id) REFERENCES items(id)
+				)`,
+				`CREATE INDEX idx_purchase_order_lines_order_id ON purchase_order_lines(order_id)`,
+				`CREATE INDEX idx_purchase_order_lines_item_id ON purchase_order_lines(item_id)`,
+			)
+		},
+	},
+	{
+		Version: 12,
+		Name:    "issue requests",
+		Up: func(tx *gorm.DB) error {
+			return execAll(tx,
+				`ALTER TABLE items ADD COLUMN reserved integer NOT NULL DEFAULT 0`,
+				`CREATE TABLE issue_requests (
+					id integer PRIMARY KEY AUTOINCREMENT,
+					number text NOT NULL,
+					status text NOT NULL DEFAULT 'pending',
+					requester text NOT NULL,
+					department text NOT NULL DEFAULT '',
+					location_id integer NOT NULL,
+					comment text NOT NULL DEFAULT '',
+					decision text NOT NULL DEFAULT '',
+					user_id integer,
+					user_name text NOT NULL DEFAULT '',
+					decided_by text NOT NULL DEFAULT '',
+					decided_at datetime,
+					issued_by text NOT NULL DEFAULT '',
+					issued_at datetime,
+					created_at datetime,
+					updated_at datetime,
+					CONSTRAINT fk_issue_requests_location FOREIGN KEY (location_id) REFERENCES locations(id)
+				)`,
+				`CREATE UNIQUE INDEX idx_issue_requests_number ON issue_requests(number)`,
+				`CREATE INDEX idx_issue_requests_status ON issue_requests(status)`,
+				`CREATE INDEX idx_issue_requests_department ON issue_requests(department)`,
+				`CREATE TABLE issue_request_lines (
+					id integer PRIMARY KEY AUTOINCREMENT,
+					request_id integer NOT NULL,
+					item_id integer NOT NULL,
+					quantity integer NOT NULL,
+					CONSTRAINT fk_issue_requests_lines FOREIGN KEY (request_id) REFERENCES issue_requests(id),
+					CONSTRAINT fk_issue_request_lines_item FOREIGN KEY (item_id) REFERENCES items(id)
+				)`,
+				`CREATE INDEX idx_issue_request_lines_request_id ON issue_request_lines(request_id)`,
+				`CREATE INDEX idx_issue_request_lines_item_id ON issue_request_lines(item_id)`,
+			)
+		},
+	},
+	{
+		Version: 13,
+		Name:    "stock reservations",
+		Up: func(tx *gorm.DB) error {
+			// Approved requests so far only bumped items.reserved; give them
+			// reservations of their own and recount the column from those.
+			return execAll(tx,
+				`CREATE TABLE reservations (
+					id integer PRIMARY KEY AUTOINCREMENT,
+					item_id integer NOT NULL,
+					quantity integer NOT NULL,
+					holder text NOT NULL,
+					comment text NOT NULL DEFAULT '',
+					expires_at datetime,
+					request_id integer,
+					user_id integer,
+					user_name text NOT NULL DEFAULT '',
+					created_at datetime,
+					updated_at datetime,
+					CONSTRAINT fk_reservations_item FOREIGN KEY (item_id) REFERENCES items(id)
+				)`,
+				`CREATE INDEX idx_reservations_item_id ON reservations(item_id)`,
+				`CREATE INDEX idx_reservations_expires_at ON reservations(expires_at)`,
+				`CREATE INDEX idx_reservations_request_id ON reservations(request_id)`,
+				`INSERT INTO reservations (item_id, quantity, holder, comment, request_id, user_id, user_name, created_at, updated_at)
+					SELECT l.item_id, l.quantity, r.number, r.requester, r.id, r.user_id, r.user_name, r.decided_at, r.decided_at
+					FROM issue_request_lines l JOIN issue_requests r ON r.id = l.request_id
+					WHERE r.status = 'approved'`,
+				`UPDATE items SET reserved = COALESCE((SELECT SUM(quantity) FROM reservations WHERE reservations.item_id = items.id), 0)`,
+			)
+		},
+	},
+	{
+		Version: 14,
+		Name:    "item cost and valuation",
+		Up: func(tx *gorm.DB) error {
+			// The cost of stock already on hand is unknown: it gets a layer
+			// at zero value, matching the zero cost of its past movements.
+			// Stock the ledger does not explain, such as balances from before
+			// movements were recorded, gets an opening movement so the sums
+			// of the ledger match the balances.
+			err := execAll(tx,
+				`ALTER TABLE items ADD COLUMN cost_method text NOT NULL DEFAULT 'average'`,
+				`ALTER TABLE stock_movements ADD COLUMN unit_cost integer NOT NULL DEFAULT 0`,
+				`ALTER TABLE stock_movements ADD COLUMN cost integer NOT NULL DEFAULT 0`,
+				`CREATE TABLE cost_layers (
+					id integer PRIMARY KEY AUTOINCREMENT,
+					item_id integer NOT NULL,
+					quantity integer NOT NULL,
+					value integer NOT NULL,
+					created_at datetime,
+					CONSTRAINT fk_cost_layers_item FOREIGN KEY (item_id) REFERENCES items(id)
+				)`,
+				`CREATE INDEX idx_cost_layers_item_id ON cost_layers(item_id)`,
+				`INSERT INTO cost_layers (item_id, quantity, value, created_at)
+					SELECT id, quantity, 0, CURRENT_TIMESTAMP FROM items WHERE quantity > 0`,
+			)
+			if err != nil {
+				return err
+			}
+			return tx.Exec(`INSERT INTO stock_movements (item_id, delta, reason, comment, reference, balance, location_id, created_at)
+				SELECT b.item_id, b.quantity - COALESCE(m.delta, 0), 'adjust', 'Начальный остаток', '', i.quantity, b.location_id, ?
+				FROM stock_balances b
+				JOIN items i ON i.id = b.item_id
+				LEFT JOIN (SELECT item_id, location_id, SUM(delta) AS delta FROM stock_movements GROUP BY item_id, location_id) m
+					ON m.item_id = b.item_id AND m.location_id = b.location_id
+				WHERE b.quantity <> COALESCE(m.delta, 0)`, time.Now()).Error
+		},
+	},
+	{
+		Version: 15,
+		Name:    "reservations by location",
+		Up: func(tx *gorm.DB) error {
+			// Reservations of approved requests hold stock where the request
+			// is issued from; manual ones keep holding it at any location.
+			return execAll(tx,
+				`ALTER TABLE reservations ADD COLUMN location_id integer REFERENCES locations(id)`,
+				`CREATE INDEX idx_reservations_location_id ON reservations(location_id)`,
+				`UPDATE reservations SET location_id = (SELECT location_id FROM issue_requests WHERE issue_requests.id = reservations.request_id)
+					WHERE request_id IS NOT NULL`,
+			)
+		},
+	},
+	{
+		Version: 16,
+		Name:    "names of deleted suppliers and locations",
+		Up: func(tx *gorm.DB) error {
+			// A deleted supplier or location no longer holds its name.
+			return execAll(tx,
+				`DROP INDEX idx_suppliers_name`,
+				`CREATE UNIQUE INDEX idx_suppliers_name ON suppliers(name) WHERE deleted_at IS NULL`,
+				`DROP INDEX idx_locations_name`,
+				`CREATE UNIQUE INDEX idx_locations_name ON locations(name) WHERE deleted_at IS NULL`,
+			)
+		},
+	},
+}
+
+// LatestSchemaVersion is the schema version this build expects.
+func LatestSchemaVersion() int {
+	return migrations[len(migrations)-1].Version
+}
+
+// SchemaVersion returns the highest applied migration version (0 for a fresh or legacy database).
+func (s *DatabaseService) SchemaVersion() (int, error) {
+	if err := s.DB.AutoMigrate(&schemaMigration{}); err != nil {
+		return 0, fmt.Errorf("schema_migrations: %w", err)
+	}
+	var version int
+	err := s.DB.Model(&schemaMigration{}).Select("COALESCE(MAX(version), 0)").Scan(&version).Error
+	return version, err
+}
+
+// Migrate brings the database up to LatestSchemaVersion. Before the first
+// pending migration an online backup of the database file is written to the
+// backup directory next to it, where backup retention covers it. Each migration is applied in its own transaction; on failure the
+// database stays at the last successfully applied version.
+func (s *DatabaseService) Migrate() error {
+	current, err := s.SchemaVersion()
+	if err != nil {
+		return err
+	}
+	latest := LatestSchemaVersion()
+	if current > latest {
+		return fmt.Errorf("%w (database v%d, application v%d)", ErrSchemaTooNew, current, latest)
+	}
+	if current == latest {
+		return nil
+	}
+
+	if s.hasUserTables() {
+		backup, err := s.backupBeforeMigrate()
+		if err != nil {
+			return fmt.Errorf("pre-migration backup: %w", err)
+		}
+		log.Printf("database backed up to %s before migrating v%d -> v%d", backup, current, latest)
+	}
+
+	for _, m := range migrations {
+		if m.Version <= current {
+			continue
+		}
+		err := s.DB.Transaction(func(tx *gorm.DB) error {
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("migration %d (%s): %w", m.Version, m.Name, err)
+		}
+		log.Printf("applied migration %d: %s", m.Version, m.Name)
+	}
+	return nil
+}
+
+// execAll runs statements in order, stopping at the first error.
+func execAll(tx *gorm.DB, statements ...string) error {
+	for _, stmt := range statements {
+		if err := tx.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasUserTables reports whether the database already holds application data,
+// i.e. whether a backup before migrating is worth taking.
+func (s *DatabaseService) hasUserTables() bool {
+	var n int64
+	s.DB.Raw("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' AND name <> 'schema_migrations'").Scan(&n)
+	return n > 0
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Frozen copies of the models as they were when a migration was written.
+// Migrations create tables from these rather than from package models, so a
+// later change to a model never changes what an old migration does.
+
+// itemV1 is models.Item at schema version 1.
+type itemV1 struct {
+	ID        uint      `gorm:"primaryKey"`
+	Name      string    `gorm:"not null;index"`
+	Quantity  int       `gorm:"not null;default:0;index"`
+	Comment   string    `gorm:"type:text"`
+	UpdatedAt time.Time `gorm:"index"`
+	CreatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (itemV1) TableName() string { return "items" }
+
+// stockMovementV1 is models.StockMovement at schema version 1.
+type stockMovementV1 struct {
+	ID        uint      `gorm:"primaryKey"`
+	ItemID    uint      `gorm:"not null;index"`
+	Item      *itemV1   `gorm:"foreignKey:ItemID"`
+	Delta     int       `gorm:"not null"`
+	Reason    string    `gorm:"not null;index"`
+	Comment   string    `gorm:"type:text"`
+	Reference string    `gorm:"index"`
+	Balance   int       `gorm:"not null"`
+	CreatedAt time.Time `gorm:"index"`
+}
+
+func (stockMovementV1) TableName() string { return "stock_movements" }
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+// shippedV1Schema is the schema migration 1 creates. It must never change:
+// corrections go into a new migration.
+var shippedV1Schema = []string{
+	"CREATE INDEX `idx_items_deleted_at` ON `items`(`deleted_at`)",
+	"CREATE INDEX `idx_items_name` ON `items`(`name`)",
+	"CREATE INDEX `idx_items_quantity` ON `items`(`quantity`)",
+	"CREATE INDEX `idx_items_updated_at` ON `items`(`updated_at`)",
+	"CREATE INDEX `idx_stock_movements_created_at` ON `stock_movements`(`created_at`)",
+	"CREATE INDEX `idx_stock_movements_item_id` ON `stock_movements`(`item_id`)",
+	"CREATE INDEX `idx_stock_movements_reason` ON `stock_movements`(`reason`)",
+	"CREATE INDEX `idx_stock_movements_reference` ON `stock_movements`(`reference`)",
+	"CREATE TABLE `items` (`id` integer PRIMARY KEY AUTOINCREMENT,`name` text NOT NULL,`quantity` integer NOT NULL DEFAULT 0," +
+		"`comment` text,`updated_at` datetime,`created_at` datetime,`deleted_at` datetime)",
+	"CREATE TABLE `stock_movements` (`id` integer PRIMARY KEY AUTOINCREMENT,`item_id` integer NOT NULL,`delta` integer NOT NULL," +
+		"`reason` text NOT NULL,`comment` text,`reference` text,`balance` integer NOT NULL,`created_at` datetime," +
+		"CONSTRAINT `fk_stock_movements_item` FOREIGN KEY (`item_id`) REFERENCES `items`(`id`))",
+}
+
+func TestMigrationV1Schema(t *testing.T) {
+	db, err := NewDatabaseService(t.TempDir(), "test.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := migrations[0].Up(db.DB); err != nil {
+		t.Fatal(err)
+	}
+	var schema []string
+	err = db.DB.Raw(`SELECT sql FROM sqlite_master WHERE sql IS NOT NULL AND name <> 'sqlite_sequence' ORDER BY name`).
+		Scan(&schema).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.Join(schema, "\n"), strings.Join(shippedV1Schema, "\n"); got != want {
+		t.Fatalf("migration 1 schema changed:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestMigrateFreshDatabase(t *testing.T) {
+	db := newTestDB(t)
+	version, err := db.SchemaVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != LatestSchemaVersion() {
+		t.Fatalf("schema version %d, want %d", version, LatestSchemaVersion())
+	}
+}
+
+func TestMigrationV3PrecisionFromUnit(t *testing.T) {
+	db, err := NewDatabaseService(t.TempDir(), "test.db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	for _, m := range migrations[:2] {
+		if err := m.Up(db.DB); err != nil {
+			t.Fatal(err)
+		}
+	}
+	units := map[string]int{"КГ.": 3, "М": 2, "См": 1, "шт": 0}
+	for unit := range units {
+		err := db.DB.Exec(`INSERT INTO items (name, sku, unit) VALUES (?, ?, ?)`, "item "+unit, unit, unit).Error
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := migrations[2].Up(db.DB); err != nil {
+		t.Fatal(err)
+	}
+	for unit, want := range units {
+		var got int
+		if err := db.DB.Raw(`SELECT precision FROM items WHERE unit = ?`, unit).Scan(&got).Error; err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("unit %q: precision %d, want %d", unit, got, want)
+		}
+	}
+}
@@ -0,0 +1,427 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"goods_wails_app/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrDuplicateSupplier is returned when another supplier already has the name.
+	ErrDuplicateSupplier = errors.New("supplier name already in use")
+	// ErrSupplierHasOrders prevents deleting a supplier with orders still open.
+	ErrSupplierHasOrders = errors.New("supplier has open purchase orders")
+	// ErrOrderStatus is returned for an operation the order's status does not allow.
+	ErrOrderStatus = errors.New("not allowed in the order's status")
+)
+
+// orderNumber is the number of a purchase order, used as the reference of
+// its receipts.
+func orderNumber(id uint) string {
+	return fmt.Sprintf("PO-%06d", id)
+}
+
+// ListSuppliers returns all suppliers ordered by name.
+func (s *DatabaseService) ListSuppliers() ([]models.Supplier, error) {
+	var suppliers []models.Supplier
+	err := s.DB.Order("name asc").Find(&suppliers).Error
+	return suppliers, err
+}
+
+// CreateSupplier adds a supplier.
+func (s *DatabaseService) CreateSupplier(in models.SupplierInput) (*models.Supplier, error) {
+	sup := &models.Supplier{}
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := applySupplierInput(tx, sup, in); err != nil {
+			return err
+		}
+		return tx.Create(sup).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sup, nil
+}
+
+// UpdateSupplier changes a supplier.
+func (s *DatabaseService) UpdateSupplier(id uint, in models.SupplierInput) (*models.Supplier, error) {
+	sup := &models.Supplier{}
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(sup, id).Error; err != nil {
+			return err
+		}
+		if err := applySupplierInput(tx, sup, in); err != nil {
+			return err
+		}
+		return tx.Save(sup).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sup, nil
+}
+
+// DeleteSupplier soft-deletes a supplier without open orders. Its closed
+// orders keep referring to it.
+func (s *DatabaseService) DeleteSupplier(id uint) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		var sup models.Supplier
+		if err := tx.First(&sup, id).Error; err != nil {
+			return err
+		}
+		var n int64
+		if err := tx.Model(&models.PurchaseOrder{}).Where("supplier_id = ? AND status <> ?", id, models.OrderClosed).Count(&n).Error; err != nil {
+			return err
+		}
+		if n > 0 {
+			return fmt.Errorf("%w: %s", ErrSupplierHasOrders, sup.Name)
+		}
+		return tx.Delete(&sup).Error
+	})
+}
+
+// ListPurchaseOrders returns orders with the given status and supplier;
+// empty status and zero supplier match all. Newest first, lines not loaded.
+func (s *DatabaseService) ListPurchaseOrders(status string, supplierID uint) ([]models.PurchaseOrder, error) {
+	var orders []models.PurchaseOrder
+	q := s.DB.Preload("Supplier", unscoped).Preload("Location", unscoped).Order("id desc")
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	if supplierID != 0 {
+		q = q.Where("supplier_id = ?", supplierID)
+	}
+	err := q.Find(&orders).Error
+	return orders, err
+}
+
+// GetPurchaseOrder returns an order with its lines and what is outstanding.
+func (s *DatabaseService) GetPurchaseOrder(id uint) (*models.PurchaseOrder, error) {
+	var order models.PurchaseOrder
+	err := s.DB.Preload("Supplier", unscoped).Preload("Location", unscoped).
+		Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("id asc") }).
+		Preload("Lines.Item", unscoped).
+		First(&order, id).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range order.Lines {
+		order.Lines[i].Outstanding = outstanding(order.Lines[i])
+	}
+	return &order, nil
+}
+
+// CreatePurchaseOrder creates a draft order.
+func (s *DatabaseService) CreatePurchaseOrder(in models.PurchaseOrderInput) (*models.PurchaseOrder, error) {
+	order := &models.PurchaseOrder{Status: models.OrderDraft}
+	order.UserID, order.UserName = s.actor()
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := applyOrderInput(tx, order, in); err != nil {
+			return err
+		}
+		if err := tx.Omit("Lines").Create(order).Error; err != nil {
+			return err
+		}
+		order.Number = orderNumber(order.ID)
+		if err := tx.Model(order).Update("number", order.Number).Error; err != nil {
+			return err
+		}
+		return replaceOrderLines(tx, order.ID, in.Lines)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetPurchaseOrder(order.ID)
+}
+
+// UpdatePurchaseOrder changes a draft order, replacing its lines.
+func (s *DatabaseService) UpdatePurchaseOrder(id uint, in models.PurchaseOrderInput) (*models.PurchaseOrder, error) {
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		order, err := orderInStatus(tx, id, models.OrderDraft)
+		if err != nil {
+			return err
+		}
+		if err := applyOrderInput(tx, order, in); err != nil {
+			return err
+		}
+		if err := tx.Omit("Lines").Save(order).Error; err != nil {
+			return err
+		}
+		return replaceOrderLines(tx, order.ID, in.Lines)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetPurchaseOrder(id)
+}
+
+// DeletePurchaseOrder removes a draft order. Orders that were sent are
+// closed instead, so their history stays.
+func (s *DatabaseService) DeletePurchaseOrder(id uint) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		order, err := orderInStatus(tx, id, models.OrderDraft)
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("order_id = ?", id).Delete(&models.PurchaseOrderLine{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(order).Error
+	})
+}
+
+// SendPurchaseOrder marks a draft order as sent to the supplier; from then
+// on goods can be received against it and it can no longer be edited.
+func (s *DatabaseService) SendPurchaseOrder(id uint) (*models.PurchaseOrder, error) {
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		order, err := orderInStatus(tx, id, models.OrderDraft)
+		if err != nil {
+			return err
+		}
+		var n int64
+		if err := tx.Model(&models.PurchaseOrderLine{}).Where("order_id = ?", id).Count(&n).Error; err != nil {
+			return err
+		}
+		if n == 0 {
+			return fmt.Errorf("order %s has no lines", order.Number)
+		}
+		return tx.Model(order).Updates(map[string]interface{}{"status": models.OrderSent, "sent_at": time.Now()}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetPurchaseOrder(id)
+}
+
+// ClosePurchaseOrder closes an order by hand, e.g. when the rest will not
+// be delivered. Whatever is outstanding is no longer expected.
+func (s *DatabaseService) ClosePurchaseOrder(id uint) (*models.PurchaseOrder, error) {
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		order, err := orderInStatus(tx, id, models.OrderDraft, models.OrderSent, models.OrderPartial)
+		if err != nil {
+			return err
+		}
+		return tx.Model(order).Updates(map[string]interface{}{"status": models.OrderClosed, "closed_at": time.Now()}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetPurchaseOrder(id)
+}
+
+// ReceivePurchaseOrder books the goods that arrived against a sent order at
+// a location (0 for the order's location), with the order number as the
+// movement reference. No line may exceed what is outstanding. The order
+// becomes partially received, or closed once every line is complete.
+func (s *DatabaseService) ReceivePurchaseOrder(id uint, locationID uint, receipt []models.ReceiptLine, comment string) (*models.PurchaseOrder, error) {
+	if len(receipt) == 0 {
+		return nil, fmt.Errorf("nothing to receive")
+	}
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		order, err := orderInStatus(tx, id, models.OrderSent, models.OrderPartial)
+		if err != nil {
+			return err
+		}
+		loc := order.LocationID
+		if locationID != 0 {
+			if loc, err = resolveLocation(tx, locationID); err != nil {
+				return err
+			}
+		}
+		var lines []models.PurchaseOrderLine
+		if err := tx.Preload("Item", unscoped).Where("order_id = ?", id).Find(&lines).Error; err != nil {
+			return err
+		}
+		byID := make(map[uint]*models.PurchaseOrderLine, len(lines))
+		for i := range lines {
+			byID[lines[i].ID] = &lines[i]
+		}
+		seen := map[uint]bool{}
+		for _, rl := range receipt {
+			line, ok := byID[rl.LineID]
+			if !ok {
+				return fmt.Errorf("line %d is not part of order %s", rl.LineID, order.Number)
+			}
+			if seen[rl.LineID] {
+				return fmt.Errorf("%s: line received twice", line.Item.Name)
+			}
+			seen[rl.LineID] = true
+			qty, err := receiveOrderLine(tx, order, line, loc, rl, comment)
+			if err != nil {
+				return fmt.Errorf("%s: %w", line.Item.Name, err)
+			}
+			line.Received += qty
+			if err := tx.Model(line).Update("received", line.Received).Error; err != nil {
+				return err
+			}
+		}
+		updates := map[string]interface{}{"status": models.OrderClosed, "closed_at": time.Now()}
+		for _, line := range lines {
+			if outstanding(line) > 0 {
+				updates = map[string]interface{}{"status": models.OrderPartial}
+				break
+			}
+		}
+		return tx.Model(order).Updates(updates).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetPurchaseOrder(id)
+}
+
+// receiveOrderLine books one receipt line and returns the quantity received.
+func receiveOrderLine(tx *gorm.DB, order *models.PurchaseOrder, line *models.PurchaseOrderLine, loc uint, rl models.ReceiptLine, comment string) (models.Quantity, error) {
+	var item models.Item
+	if err := tx.First(&item, line.ItemID).Error; err != nil {
+		return 0, err
+	}
+	qty := rl.Quantity
+	if item.TrackSerials {
+		n := 0
+		for _, code := range rl.Serials {
+			if strings.TrimSpace(code) != "" {
+				n++
+			}
+		}
+		if qty != 0 && qty != models.Units(int64(n)) {
+			return 0, fmt.Errorf("quantity %s does not match %d serial numbers", qty, n)
+		}
+		qty = models.Units(int64(n))
+	}
+	if qty <= 0 {
+		return 0, fmt.Errorf("quantity must be positive")
+	}
+	if qty > outstanding(*line) {
+		return 0, fmt.Errorf("%s exceeds the outstanding %s", qty, outstanding(*line))
+	}
+	if item.TrackSerials {
+		_, err := addSerials(tx, &item, loc, rl.Serials, comment, order.Number, &line.Price)
+		return qty, err
+	}
+	m := &models.StockMovement{
+		Delta:      qty,
+		Reason:     models.MovementReceive,
+		Comment:    comment,
+		Reference:  order.Number,
+		LocationID: &loc,
+		UnitCost:   line.Price,
+		CostGiven:  true, // the ordered price stands, free goods included
+	}
+	if rl.Lot != nil {
+		if !item.TrackLots {
+			return 0, ErrNotLotTracked
+		}
+		lot, err := findOrCreateLot(tx, item.ID, loc, *rl.Lot)
+		if err != nil {
+			return 0, err
+		}
+		m.LotID = &lot.ID
+	}
+	if err := adjustQuantity(tx, item.ID, qty, nil, &item); err != nil {
+		return 0, err
+	}
+	return qty, recordMovement(tx, &item, m)
+}
+
+// outstanding is what is still to be delivered on a line.
+func outstanding(line models.PurchaseOrderLine) models.Quantity {
+	return max(line.Ordered-line.Received, 0)
+}
+
+// orderInStatus loads an order and checks that it is in one of statuses.
+func orderInStatus(tx *gorm.DB, id uint, statuses ...string) (*models.PurchaseOrder, error) {
+	var order models.PurchaseOrder
+	if err := tx.First(&order, id).Error; err != nil {
+		return nil, err
+	}
+	for _, st := range statuses {
+		if order.Status == st {
+			return &order, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s is %s", ErrOrderStatus, order.Number, order.Status)
+}
+
+// applyOrderInput validates the header fields of in and copies them onto order.
+func applyOrderInput(tx *gorm.DB, order *models.PurchaseOrder, in models.PurchaseOrderInput) error {
+	var sup models.Supplier
+	if err := tx.First(&sup, in.SupplierID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("supplier %d not found", in.SupplierID)
+		}
+		return err
+	}
+	loc, err := resolveLocation(tx, in.LocationID)
+	if err != nil {
+		return err
+	}
+	order.SupplierID = sup.ID
+	order.LocationID = loc
+	order.Comment = strings.TrimSpace(in.Comment)
+	order.ExpectedAt = in.ExpectedAt
+	return nil
+}
+
+// replaceOrderLines validates lines and makes them the lines of the order.
+func replaceOrderLines(tx *gorm.DB, orderID uint, lines []models.PurchaseOrderLineInput) error {
+	if err := tx.Where("order_id = ?", orderID).Delete(&models.PurchaseOrderLine{}).Error; err != nil {
+		return err
+	}
+	seen := map[uint]bool{}
+	for _, in := range lines {
+		var item models.Item
+		if err := tx.First(&item, in.ItemID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("item %d not found", in.ItemID)
+			}
+			return err
+		}
+		if seen[item.ID] {
+			return fmt.Errorf("%s is ordered twice", item.Name)
+		}
+		seen[item.ID] = true
+		if in.Ordered <= 0 {
+			return fmt.Errorf("%s: ordered quantity must be positive", item.Name)
+		}
+		if err := checkPrecision(&item, in.Ordered); err != nil {
+			return fmt.Errorf("%s: %w", item.Name, err)
+		}
+		if in.Price < 0 {
+			return fmt.Errorf("%s: price must not be negative", item.Name)
+		}
+		line := models.PurchaseOrderLine{OrderID: orderID, ItemID: item.ID, Ordered: in.Ordered, Price: in.Price}
+		if err := tx.Create(&line).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applySupplierInput validates in and copies it onto sup.
+func applySupplierInput(tx *gorm.DB, sup *models.Supplier, in models.SupplierInput) error {
+	name := strings.TrimSpace(in.Name)
+	if name == "" {
+		return fmt.Errorf("supplier name is required")
+	}
+	// Deleted suppliers give up their name.
+	var n int64
+	if err := tx.Model(&models.Supplier{}).Where("name = ? AND id <> ?", name, sup.ID).Count(&n).Error; err != nil {
+		return err
+	}
+	if n > 0 {
+		return fmt.Errorf("%w: %s", ErrDuplicateSupplier, name)
+	}
+	sup.Name = name
+	sup.INN = strings.TrimSpace(in.INN)
+	sup.Contact = strings.TrimSpace(in.Contact)
+	sup.Phone = strings.TrimSpace(in.Phone)
+	sup.Email = strings.TrimSpace(in.Email)
+	sup.Comment = strings.TrimSpace(in.Comment)
+	return nil
+}
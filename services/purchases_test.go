@@ -0,0 +1,26 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"goods_wails_app/models"
+)
+
+func TestReuseNameOfDeletedSupplier(t *testing.T) {
+	db := newTestDB(t)
+	in := models.SupplierInput{Name: "ООО «Ромашка»"}
+	sup, err := db.CreateSupplier(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.CreateSupplier(in); !errors.Is(err, ErrDuplicateSupplier) {
+		t.Fatalf("second live supplier: %v, want ErrDuplicateSupplier", err)
+	}
+	if err := db.DeleteSupplier(sup.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.CreateSupplier(in); err != nil {
+		t.Fatalf("reusing the name of a deleted supplier: %v", err)
+	}
+}
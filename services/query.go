@@ -0,0 +1,79 @@
+package services
+
+import (
+	"strings"
+
+	"goods_wails_app/models"
+
+	"gorm.io/gorm"
+)
+
+// itemSortColumns maps ItemQuery.SortBy values to columns.
+var itemSortColumns = map[string]string{
+	models.SortByName:     "name",
+	models.SortByQuantity: "quantity",
+	models.SortByUpdated:  "updated_at",
+}
+
+// QueryItems returns one page of items matching q together with the total match count.
+func (s *DatabaseService) QueryItems(q models.ItemQuery) (models.ItemPage, error) {
+	page := models.ItemPage{Items: []models.Item{}}
+	base := s.DB.Model(&models.Item{}).Scopes(itemFilter(q))
+	if err := base.Count(&page.Total).Error; err != nil {
+		return page, err
+	}
+	find := s.DB.Preload("Barcodes").Preload("Balances", "quantity <> 0").Scopes(itemFilter(q), itemOrder(q))
+	if q.Offset > 0 {
+		find = find.Offset(q.Offset)
+	}
+	if q.Limit > 0 {
+		find = find.Limit(q.Limit)
+	}
+	if err := find.Find(&page.Items).Error; err != nil {
+		return page, err
+	}
+	return page, nil
+}
+
+// itemFilter applies the filter part of q (everything except sorting and paging).
+func itemFilter(q models.ItemQuery) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if search := strings.TrimSpace(q.Search); search != "" {
+			pattern := "%" + escapeLike(strings.ToLower(search)) + "%"
+			db = db.Where(`unicode_lower(name) LIKE ? ESCAPE '\' OR unicode_lower(comment) LIKE ? ESCAPE '\' OR unicode_lower(sku) LIKE ? ESCAPE '\'
+				OR EXISTS (SELECT 1 FROM item_serials WHERE item_serials.item_id = items.id AND unicode_lower(item_serials.serial) LIKE ? ESCAPE '\')`,
+				pattern, pattern, pattern, pattern)
+		}
+		if q.MinQuantity != nil {
+			db = db.Where("quantity >= ?", *q.MinQuantity)
+		}
+		if q.MaxQuantity != nil {
+			db = db.Where("quantity <= ?", *q.MaxQuantity)
+		}
+		if q.UpdatedSince != nil && !q.UpdatedSince.IsZero() {
+			db = db.Where("updated_at >= ?", *q.UpdatedSince)
+		}
+		return db
+	}
+}
+
+// itemOrder applies the sort part of q. id is a tiebreaker so paging is stable.
+func itemOrder(q models.ItemQuery) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		col, ok := itemSortColumns[q.SortBy]
+		if !ok {
+			col = "name"
+		}
+		dir := " asc"
+		if q.SortDesc {
+			dir = " desc"
+		}
+		return db.Order(col + dir).Order("id" + dir)
+	}
+}
+
+// escapeLike escapes LIKE wildcards so user input matches literally.
+func escapeLike(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return r.Replace(s)
+}
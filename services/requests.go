@@ -0,0 +1,300 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"goods_wails_app/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrRequestStatus is returned for an operation the request's status does not allow.
+var ErrRequestStatus = errors.New("not allowed in the request's status")
+
+// requestNumber is the number of an issue request, used as the reference
+// of the withdrawals booked when it is issued.
+func requestNumber(id uint) string {
+	return fmt.Sprintf("REQ-%06d", id)
+}
+
+// ListIssueRequests returns requests with the given status (empty for all),
+// newest first, lines not loaded.
+func (s *DatabaseService) ListIssueRequests(status string) ([]models.IssueRequest, error) {
+	var requests []models.IssueRequest
+	q := s.DB.Preload("Location", unscoped).Order("id desc")
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	err := q.Find(&requests).Error
+	return requests, err
+}
+
+// GetIssueRequest returns a request with its lines.
+func (s *DatabaseService) GetIssueRequest(id uint) (*models.IssueRequest, error) {
+	var req models.IssueRequest
+	err := s.DB.Preload("Location", unscoped).
+		Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("id asc") }).
+		Preload("Lines.Item", unscoped).
+		First(&req, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// CreateIssueRequest registers a pending request.
+func (s *DatabaseService) CreateIssueRequest(in models.IssueRequestInput) (*models.IssueRequest, error) {
+	req := &models.IssueRequest{Status: models.RequestPending}
+	req.UserID, req.UserName = s.actor()
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := applyRequestInput(tx, req, in); err != nil {
+			return err
+		}
+		if err := tx.Omit("Lines").Create(req).Error; err != nil {
+			return err
+		}
+		req.Number = requestNumber(req.ID)
+		if err := tx.Model(req).Update("number", req.Number).Error; err != nil {
+			return err
+		}
+		return replaceRequestLines(tx, req.ID, in.Lines)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetIssueRequest(req.ID)
+}
+
+// UpdateIssueRequest changes a pending request, replacing its lines.
+func (s *DatabaseService) UpdateIssueRequest(id uint, in models.IssueRequestInput) (*models.IssueRequest, error) {
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		req, err := requestInStatus(tx, id, models.RequestPending)
+		if err != nil {
+			return err
+		}
+		if err := applyRequestInput(tx, req, in); err != nil {
+			return err
+		}
+		if err := tx.Omit("Lines").Save(req).Error; err != nil {
+			return err
+		}
+		return replaceRequestLines(tx, req.ID, in.Lines)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetIssueRequest(id)
+}
+
+// ApproveIssueRequest approves a pending request and reserves its lines at
+// the request's location, held by the request number. It fails with
+// ErrInsufficientQuantity when an item does not have that much available
+// stock there, leaving nothing reserved.
+func (s *DatabaseService) ApproveIssueRequest(id uint, comment string) (*models.IssueRequest, error) {
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		req, err := requestInStatus(tx, id, models.RequestPending)
+		if err != nil {
+			return err
+		}
+		var lines []models.IssueRequestLine
+		if err := tx.Preload("Item", unscoped).Where("request_id = ?", id).Order("id asc").Find(&lines).Error; err != nil {
+			return err
+		}
+		if len(lines) == 0 {
+			return fmt.Errorf("request %s has no lines", req.Number)
+		}
+		for _, line := range lines {
+			r := &models.Reservation{
+				ItemID:     line.ItemID,
+				Quantity:   line.Quantity,
+				Holder:     req.Number,
+				Comment:    req.Requester,
+				RequestID:  &req.ID,
+				LocationID: &req.LocationID,
+			}
+			r.UserID, r.UserName = s.actor()
+			if err := reserveStock(tx, r); err != nil {
+				return fmt.Errorf("%s: %w", line.Item.Name, err)
+			}
+		}
+		return tx.Model(req).Updates(s.requestDecision(models.RequestApproved, comment)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetIssueRequest(id)
+}
+
+// RejectIssueRequest rejects a pending or approved request with a reason.
+// The reservation of an approved request is released.
+func (s *DatabaseService) RejectIssueRequest(id uint, reason string) (*models.IssueRequest, error) {
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		req, err := requestInStatus(tx, id, models.RequestPending, models.RequestApproved)
+		if err != nil {
+			return err
+		}
+		if req.Status == models.RequestApproved {
+			if err := releaseRequest(tx, id); err != nil {
+				return err
+			}
+		}
+		return tx.Model(req).Updates(s.requestDecision(models.RequestRejected, reason)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetIssueRequest(id)
+}
+
+// FulfillIssueRequest hands out an approved request from its location:
+// its reservations are released and every line is withdrawn with the
+// request number as reference.
+func (s *DatabaseService) FulfillIssueRequest(id uint, comment string) (*models.IssueRequest, error) {
+	var issued []*models.Item
+	var wasLow []bool
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		req, err := requestInStatus(tx, id, models.RequestApproved)
+		if err != nil {
+			return err
+		}
+		var lines []models.IssueRequestLine
+		if err := tx.Where("request_id = ?", id).Order("id asc").Find(&lines).Error; err != nil {
+			return err
+		}
+		if err := releaseRequest(tx, id); err != nil {
+			return err
+		}
+		loc := req.LocationID
+		for _, line := range lines {
+			var item models.Item
+			if err := adjustQuantity(tx, line.ItemID, -line.Quantity, nil, &item); err != nil {
+				if errors.Is(err, ErrInsufficientQuantity) {
+					return fmt.Errorf("%w: %s", err, item.Name)
+				}
+				return err
+			}
+			err := recordMovement(tx, &item, &models.StockMovement{
+				Delta:      -line.Quantity,
+				Reason:     models.MovementWithdraw,
+				Comment:    strings.TrimSpace(comment),
+				Reference:  req.Number,
+				LocationID: &loc,
+			})
+			if err != nil {
+				return fmt.Errorf("%s: %w", item.Name, err)
+			}
+			issued = append(issued, &item)
+			wasLow = append(wasLow, models.IsLowStock(item.Quantity+line.Quantity, item.MinStock))
+		}
+		_, name := s.actor()
+		return tx.Model(req).Updates(map[string]interface{}{
+			"status":    models.RequestIssued,
+			"issued_by": name,
+			"issued_at": time.Now(),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, item := range issued {
+		s.notifyLowStock(item, wasLow[i])
+	}
+	return s.GetIssueRequest(id)
+}
+
+// requestDecision is the update recording an approval or rejection by the
+// current user.
+func (s *DatabaseService) requestDecision(status, comment string) map[string]interface{} {
+	_, name := s.actor()
+	return map[string]interface{}{
+		"status":     status,
+		"decision":   strings.TrimSpace(comment),
+		"decided_by": name,
+		"decided_at": time.Now(),
+	}
+}
+
+// releaseRequest releases the reservations of an approved request.
+func releaseRequest(tx *gorm.DB, id uint) error {
+	var reservations []models.Reservation
+	if err := tx.Where("request_id = ?", id).Find(&reservations).Error; err != nil {
+		return err
+	}
+	for i := range reservations {
+		if err := releaseReservation(tx, &reservations[i], reservations[i].Quantity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// requestInStatus loads a request and checks that it is in one of statuses.
+func requestInStatus(tx *gorm.DB, id uint, statuses ...string) (*models.IssueRequest, error) {
+	var req models.IssueRequest
+	if err := tx.First(&req, id).Error; err != nil {
+		return nil, err
+	}
+	for _, st := range statuses {
+		if req.Status == st {
+			return &req, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s is %s", ErrRequestStatus, req.Number, req.Status)
+}
+
+// applyRequestInput validates the header fields of in and copies them onto req.
+func applyRequestInput(tx *gorm.DB, req *models.IssueRequest, in models.IssueRequestInput) error {
+	requester := strings.TrimSpace(in.Requester)
+	if requester == "" {
+		return fmt.Errorf("requester is required")
+	}
+	loc, err := resolveLocation(tx, in.LocationID)
+	if err != nil {
+		return err
+	}
+	req.Requester = requester
+	req.Department = strings.TrimSpace(in.Department)
+	req.LocationID = loc
+	req.Comment = strings.TrimSpace(in.Comment)
+	return nil
+}
+
+// replaceRequestLines validates lines and makes them the lines of the
+// request. Serial-tracked items are issued by serial number and cannot be
+// requested by quantity.
+func replaceRequestLines(tx *gorm.DB, requestID uint, lines []models.IssueRequestLineInput) error {
+	if err := tx.Where("request_id = ?", requestID).Delete(&models.IssueRequestLine{}).Error; err != nil {
+		return err
+	}
+	seen := map[uint]bool{}
+	for _, in := range lines {
+		var item models.Item
+		if err := tx.First(&item, in.ItemID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("item %d not found", in.ItemID)
+			}
+			return err
+		}
+		if seen[item.ID] {
+			return fmt.Errorf("%s is requested twice", item.Name)
+		}
+		seen[item.ID] = true
+		if item.TrackSerials {
+			return fmt.Errorf("%w: %s", ErrSerialTracked, item.Name)
+		}
+		if in.Quantity <= 0 {
+			return fmt.Errorf("%s: quantity must be positive", item.Name)
+		}
+		if err := checkPrecision(&item, in.Quantity); err != nil {
+			return fmt.Errorf("%s: %w", item.Name, err)
+		}
+		line := models.IssueRequestLine{RequestID: requestID, ItemID: item.ID, Quantity: in.Quantity}
+		if err := tx.Create(&line).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"goods_wails_app/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrRequestReservation is returned when a reservation of an issue request
+// is released or withdrawn against directly instead of through the request.
+var ErrRequestReservation = errors.New("reservation belongs to an issue request")
+
+// ListReservations returns the active reservations of an item (0 for all
+// items), those expiring first at the top and open-ended ones last.
+func (s *DatabaseService) ListReservations(itemID uint) ([]models.Reservation, error) {
+	var reservations []models.Reservation
+	q := s.DB.Preload("Item", unscoped).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		Order("expires_at IS NULL, expires_at asc, id asc")
+	if itemID != 0 {
+		q = q.Where("item_id = ?", itemID)
+	}
+	err := q.Find(&reservations).Error
+	return reservations, err
+}
+
+// CreateReservation holds stock of an item, at a location when one is given.
+// It fails with ErrInsufficientQuantity when less than the quantity is
+// available.
+func (s *DatabaseService) CreateReservation(in models.ReservationInput) (*models.Reservation, error) {
+	r := &models.Reservation{
+		ItemID:    in.ItemID,
+		Quantity:  in.Quantity,
+		Holder:    strings.TrimSpace(in.Holder),
+		Comment:   strings.TrimSpace(in.Comment),
+		ExpiresAt: in.ExpiresAt,
+	}
+	r.UserID, r.UserName = s.actor()
+	if r.Holder == "" {
+		return nil, fmt.Errorf("holder is required")
+	}
+	if r.ExpiresAt != nil && !r.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("expiry must be in the future")
+	}
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var item models.Item
+		if err := tx.First(&item, in.ItemID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("item %d not found", in.ItemID)
+			}
+			return err
+		}
+		if in.Quantity <= 0 {
+			return fmt.Errorf("quantity must be positive")
+		}
+		if err := checkPrecision(&item, in.Quantity); err != nil {
+			return err
+		}
+		if in.LocationID != 0 {
+			loc, err := resolveLocation(tx, in.LocationID)
+			if err != nil {
+				return err
+			}
+			r.LocationID = &loc
+		}
+		return reserveStock(tx, r)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, s.DB.Preload("Item", unscoped).First(r, r.ID).Error
+}
+
+// ReleaseReservation ends a reservation, making its stock available again.
+// Reservations of issue requests are released by rejecting the request.
+func (s *DatabaseService) ReleaseReservation(id uint) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		var r models.Reservation
+		if err := tx.First(&r, id).Error; err != nil {
+			return err
+		}
+		if r.RequestID != nil {
+			return ErrRequestReservation
+		}
+		return releaseReservation(tx, &r, r.Quantity)
+	})
+}
+
+// ExpireReservations releases every reservation past its expiry and returns
+// them. The app runs it periodically; reserving and withdrawing stock also
+// release the expired reservations of the item concerned first.
+func (s *DatabaseService) ExpireReservations() ([]models.Reservation, error) {
+	var expired []models.Reservation
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		expired, err = expireReservations(tx, time.Now(), 0)
+		return err
+	})
+	return expired, err
+}
+
+// expireReservations releases the reservations of an item (0 for all items)
+// that expired by now.
+func expireReservations(tx *gorm.DB, now time.Time, itemID uint) ([]models.Reservation, error) {
+	var expired []models.Reservation
+	q := tx.Preload("Item", unscoped).Where("expires_at IS NOT NULL AND expires_at <= ?", now)
+	if itemID != 0 {
+		q = q.Where("item_id = ?", itemID)
+	}
+	if err := q.Find(&expired).Error; err != nil {
+		return nil, err
+	}
+	for i := range expired {
+		if err := releaseReservation(tx, &expired[i], expired[i].Quantity); err != nil {
+			return nil, err
+		}
+	}
+	return expired, nil
+}
+
+// reserveStock stores r and adds its quantity to the item's reserved stock,
+// as long as that much is still available once expired reservations of the
+// item are released, and at r's location when it has one.
+func reserveStock(tx *gorm.DB, r *models.Reservation) error {
+	if _, err := expireReservations(tx, time.Now(), r.ItemID); err != nil {
+		return err
+	}
+	if r.LocationID != nil {
+		available, err := availableAt(tx, r.ItemID, *r.LocationID)
+		if err != nil {
+			return err
+		}
+		if available < r.Quantity {
+			var item models.Item
+			if err := tx.First(&item, r.ItemID).Error; err != nil {
+				return err
+			}
+			return fmt.Errorf("%w: %s %s available at this location", ErrInsufficientQuantity, max(available, 0), item.Unit)
+		}
+	}
+	res := tx.Model(&models.Item{}).
+		Where("id = ? AND quantity - reserved >= ?", r.ItemID, r.Quantity).
+		Updates(map[string]interface{}{"reserved": gorm.Expr("reserved + ?", r.Quantity), "updated_at": time.Now()})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		var item models.Item
+		if err := tx.First(&item, r.ItemID).Error; err != nil {
+			return err
+		}
+		return fmt.Errorf("%w: %s %s available", ErrInsufficientQuantity, item.Available, item.Unit)
+	}
+	return tx.Create(r).Error
+}
+
+// releaseReservation takes qty off a reservation and the item's reserved
+// stock; the reservation is removed once nothing is left of it.
+func releaseReservation(tx *gorm.DB, r *models.Reservation, qty models.Quantity) error {
+	err := tx.Model(&models.Item{}).Where("id = ?", r.ItemID).
+		Updates(map[string]interface{}{"reserved": gorm.Expr("MAX(reserved - ?, 0)", qty), "updated_at": time.Now()}).Error
+	if err != nil {
+		return err
+	}
+	r.Quantity -= qty
+	if r.Quantity <= 0 {
+		return tx.Delete(r).Error
+	}
+	return tx.Model(r).Update("quantity", r.Quantity).Error
+}
+
+// consumeReservation releases the part of a reservation that a withdrawal
+// of qty of the item at locationID takes, so the withdrawal may use the
+// held stock. It returns the reservation.
+func consumeReservation(tx *gorm.DB, id uint, itemID uint, locationID uint, qty models.Quantity) (*models.Reservation, error) {
+	var r models.Reservation
+	if err := tx.First(&r, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("reservation %d not found", id)
+		}
+		return nil, err
+	}
+	if r.ItemID != itemID {
+		return nil, fmt.Errorf("reservation %d is not for this item", id)
+	}
+	if r.RequestID != nil {
+		return nil, ErrRequestReservation
+	}
+	if r.LocationID != nil && *r.LocationID != locationID {
+		return nil, fmt.Errorf("reservation %d holds stock at another location", id)
+	}
+	return &r, releaseReservation(tx, &r, min(qty, r.Quantity))
+}
+
+// trimReservations shrinks the reservations of an item, newest first, until
+// they fit its stock again, both at locationID and overall. It is needed
+// after a stocktake finds reserved stock missing; item is reloaded.
+func trimReservations(tx *gorm.DB, item *models.Item, locationID uint) error {
+	available, err := availableAt(tx, item.ID, locationID)
+	if err != nil {
+		return err
+	}
+	if available < 0 {
+		if err := trimNewest(tx, tx.Where("item_id = ? AND location_id = ?", item.ID, locationID), -available); err != nil {
+			return err
+		}
+	}
+	if err := tx.First(item, item.ID).Error; err != nil {
+		return err
+	}
+	if item.Reserved <= item.Quantity {
+		return nil
+	}
+	if err := trimNewest(tx, tx.Where("item_id = ?", item.ID), item.Reserved-item.Quantity); err != nil {
+		return err
+	}
+	return tx.First(item, item.ID).Error
+}
+
+// trimNewest releases excess from the reservations q selects, newest first.
+func trimNewest(tx *gorm.DB, q *gorm.DB, excess models.Quantity) error {
+	var reservations []models.Reservation
+	if err := q.Order("id desc").Find(&reservations).Error; err != nil {
+		return err
+	}
+	for i := 0; i < len(reservations) && excess > 0; i++ {
+		take := min(excess, reservations[i].Quantity)
+		if err := releaseReservation(tx, &reservations[i], take); err != nil {
+			return err
+		}
+		excess -= take
+	}
+	return nil
+}
+
+// reservedAt is the SQL sum of the active reservations of an item at a
+// location, with the item and location ids as arguments.
+const reservedAt = `SELECT COALESCE(SUM(quantity), 0) FROM reservations
+	WHERE item_id = ? AND location_id = ? AND (expires_at IS NULL OR expires_at > ?)`
+
+// availableAt is the item's balance at a location less the stock reserved there.
+func availableAt(tx *gorm.DB, itemID uint, locationID uint) (models.Quantity, error) {
+	var available models.Quantity
+	err := tx.Raw(`SELECT COALESCE((SELECT quantity FROM stock_balances WHERE item_id = ? AND location_id = ?), 0) - (`+reservedAt+`)`,
+		itemID, locationID, itemID, locationID, time.Now()).Scan(&available).Error
+	return available, err
+}
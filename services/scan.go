@@ -0,0 +1,326 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"goods_wails_app/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrScanClosed is returned for changes to a committed or cancelled scan session.
+var ErrScanClosed = errors.New("scan session is closed")
+
+// LookupByCode resolves a scanned code to an item: by barcode first, then
+// by SKU, then by serial number. An unknown code is not an error; the
+// result has Found false.
+func (s *DatabaseService) LookupByCode(code string) (models.CodeLookup, error) {
+	return lookupCode(s.DB, code)
+}
+
+// StartScanSession opens a withdrawal or receipt document at a location
+// (0 for the default one).
+func (s *DatabaseService) StartScanSession(kind string, locationID uint, comment string, reference string) (*models.ScanSession, error) {
+	if kind != models.ScanWithdraw && kind != models.ScanReceive {
+		return nil, fmt.Errorf("unknown scan session kind %q", kind)
+	}
+	loc, err := resolveLocation(s.DB, locationID)
+	if err != nil {
+		return nil, err
+	}
+	session := models.ScanSession{
+		Kind:       kind,
+		Status:     models.ScanOpen,
+		LocationID: loc,
+		Comment:    strings.TrimSpace(comment),
+		Reference:  strings.TrimSpace(reference),
+	}
+	session.UserID, session.UserName = s.actor()
+	if err := s.DB.Create(&session).Error; err != nil {
+		return nil, err
+	}
+	return s.GetScanSession(session.ID)
+}
+
+// GetScanSession returns a session with its lines in scan order.
+func (s *DatabaseService) GetScanSession(id uint) (*models.ScanSession, error) {
+	var session models.ScanSession
+	err := s.DB.Preload("Location", unscoped).
+		Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("id asc") }).
+		Preload("Lines.Item", unscoped).
+		Preload("Lines.Serial").
+		First(&session, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// ListScanSessions returns sessions with the given status, or all of them
+// when status is empty, newest first. Lines are not loaded.
+func (s *DatabaseService) ListScanSessions(status string) ([]models.ScanSession, error) {
+	var sessions []models.ScanSession
+	q := s.DB.Preload("Location", unscoped).Order("id desc")
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	err := q.Find(&sessions).Error
+	return sessions, err
+}
+
+// ScanCode adds a scanned code to an open session. qty <= 0 counts as one
+// unit. Scanning an item already in the session adds to its line; a serial
+// number gets a line of its own and must suit the session: in stock at its
+// location for a withdrawal, out of stock for a receipt. An unknown code
+// adds nothing and comes back with Lookup.Found false.
+func (s *DatabaseService) ScanCode(sessionID uint, code string, qty models.Quantity) (*models.ScanResult, error) {
+	if qty <= 0 {
+		qty = models.Units(1)
+	}
+	result := &models.ScanResult{}
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		session, err := openScanSession(tx, sessionID)
+		if err != nil {
+			return err
+		}
+		if result.Lookup, err = lookupCode(tx, code); err != nil || !result.Lookup.Found {
+			return err
+		}
+		item, unit := result.Lookup.Item, result.Lookup.Serial
+		var line models.ScanLine
+		switch {
+		case item.TrackSerials && unit != nil:
+			if err := checkScannedSerial(tx, session, unit); err != nil {
+				return err
+			}
+			line = models.ScanLine{SessionID: session.ID, ItemID: item.ID, SerialID: &unit.ID, Code: result.Lookup.Code, Quantity: models.Units(1)}
+			if err := tx.Create(&line).Error; err != nil {
+				return err
+			}
+		case item.TrackSerials:
+			return fmt.Errorf("%w: scan the serial number of %s", ErrSerialTracked, item.Name)
+		default:
+			if err := checkPrecision(item, qty); err != nil {
+				return err
+			}
+			err := tx.Where("session_id = ? AND item_id = ? AND serial_id IS NULL", session.ID, item.ID).Limit(1).Find(&line).Error
+			if err != nil {
+				return err
+			}
+			line.SessionID, line.ItemID = session.ID, item.ID
+			line.Quantity += qty
+			line.Code = result.Lookup.Code
+			if err := tx.Save(&line).Error; err != nil {
+				return err
+			}
+		}
+		line.Item = item
+		line.Serial = unit
+		result.Line = &line
+		return tx.Model(session).Update("updated_at", time.Now()).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SetScanLineQuantity corrects the quantity of a line; zero removes it.
+// Serial number lines can only be removed.
+func (s *DatabaseService) SetScanLineQuantity(lineID uint, qty models.Quantity) (*models.ScanSession, error) {
+	if qty < 0 {
+		return nil, fmt.Errorf("quantity must not be negative")
+	}
+	var line models.ScanLine
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Preload("Item", unscoped).First(&line, lineID).Error; err != nil {
+			return err
+		}
+		session, err := openScanSession(tx, line.SessionID)
+		if err != nil {
+			return err
+		}
+		if qty == 0 {
+			if err := tx.Delete(&line).Error; err != nil {
+				return err
+			}
+		} else {
+			if line.SerialID != nil {
+				return fmt.Errorf("a serial number line is always one unit")
+			}
+			if err := checkPrecision(line.Item, qty); err != nil {
+				return err
+			}
+			if err := tx.Model(&line).Update("quantity", qty).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(session).Update("updated_at", time.Now()).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetScanSession(line.SessionID)
+}
+
+// CommitScanSession books all lines of a session in one transaction, as
+// WithdrawQuantity / ReceiveQuantity would, with the session's comment and
+// reference. Serial numbers are issued to the reference on withdrawal and
+// returned to stock on receipt. If any line fails nothing is booked and
+// the session stays open.
+func (s *DatabaseService) CommitScanSession(id uint) (*models.ScanSession, error) {
+	items := map[uint]*models.Item{}
+	wasLow := map[uint]bool{}
+	var order []uint
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		session, err := openScanSession(tx, id)
+		if err != nil {
+			return err
+		}
+		var lines []models.ScanLine
+		if err := tx.Preload("Item", unscoped).Preload("Serial").Where("session_id = ?", id).Order("id asc").Find(&lines).Error; err != nil {
+			return err
+		}
+		if len(lines) == 0 {
+			return fmt.Errorf("scan session is empty")
+		}
+		for _, line := range lines {
+			item, delta, err := commitScanLine(tx, session, line)
+			if err != nil {
+				return fmt.Errorf("%s: %w", line.Item.Name, err)
+			}
+			if _, seen := items[item.ID]; !seen {
+				wasLow[item.ID] = models.IsLowStock(item.Quantity-delta, item.MinStock)
+				order = append(order, item.ID)
+			}
+			items[item.ID] = item
+		}
+		return tx.Model(session).Updates(map[string]interface{}{"status": models.ScanCommitted, "closed_at": time.Now()}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, itemID := range order {
+		s.notifyLowStock(items[itemID], wasLow[itemID])
+	}
+	return s.GetScanSession(id)
+}
+
+// CancelScanSession closes a session without booking anything.
+func (s *DatabaseService) CancelScanSession(id uint) (*models.ScanSession, error) {
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		session, err := openScanSession(tx, id)
+		if err != nil {
+			return err
+		}
+		return tx.Model(session).Updates(map[string]interface{}{"status": models.ScanCancelled, "closed_at": time.Now()}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetScanSession(id)
+}
+
+// lookupCode is LookupByCode within tx. Misses are the normal case for
+// the first lookups, so Find is used to keep them out of the error log.
+func lookupCode(tx *gorm.DB, code string) (models.CodeLookup, error) {
+	code = strings.TrimSpace(code)
+	result := models.CodeLookup{Code: code}
+	if code == "" {
+		return result, fmt.Errorf("code is empty")
+	}
+	var item models.Item
+	err := tx.Preload("Barcodes").
+		Joins("JOIN item_barcodes ON item_barcodes.item_id = items.id").
+		Where("item_barcodes.code = ?", code).
+		Limit(1).Find(&item).Error
+	if err != nil || item.ID != 0 {
+		result.Found, result.MatchedBy, result.Item = item.ID != 0, models.MatchedBarcode, &item
+		return result, err
+	}
+	if err := tx.Preload("Barcodes").Where("sku = ?", code).Limit(1).Find(&item).Error; err != nil || item.ID != 0 {
+		result.Found, result.MatchedBy, result.Item = item.ID != 0, models.MatchedSKU, &item
+		return result, err
+	}
+	var unit models.ItemSerial
+	err = tx.Preload("Item.Barcodes").Preload("Location", unscoped).
+		Joins("JOIN items ON items.id = item_serials.item_id AND items.deleted_at IS NULL").
+		Where("item_serials.serial = ?", code).
+		Limit(1).Find(&unit).Error
+	if err != nil || unit.ID == 0 {
+		return result, err
+	}
+	result.Found, result.MatchedBy, result.Item, result.Serial = true, models.MatchedSerial, unit.Item, &unit
+	return result, nil
+}
+
+// openScanSession loads a session and checks that it can still be changed.
+func openScanSession(tx *gorm.DB, id uint) (*models.ScanSession, error) {
+	var session models.ScanSession
+	if err := tx.First(&session, id).Error; err != nil {
+		return nil, err
+	}
+	if session.Status != models.ScanOpen {
+		return nil, fmt.Errorf("%w: #%d is %s", ErrScanClosed, session.ID, session.Status)
+	}
+	return &session, nil
+}
+
+// checkScannedSerial checks that a scanned unit suits the session and has
+// not been scanned into it already.
+func checkScannedSerial(tx *gorm.DB, session *models.ScanSession, unit *models.ItemSerial) error {
+	var n int64
+	if err := tx.Model(&models.ScanLine{}).Where("session_id = ? AND serial_id = ?", session.ID, unit.ID).Count(&n).Error; err != nil {
+		return err
+	}
+	if n > 0 {
+		return fmt.Errorf("serial %s is already scanned", unit.Serial)
+	}
+	switch {
+	case unit.Status == models.SerialWrittenOff:
+		return fmt.Errorf("serial %s is written off", unit.Serial)
+	case session.Kind == models.ScanWithdraw && (unit.Status != models.SerialInStock || unit.LocationID != session.LocationID):
+		return fmt.Errorf("serial %s is not in stock at this location", unit.Serial)
+	case session.Kind == models.ScanReceive && unit.Status == models.SerialInStock:
+		return fmt.Errorf("serial %s is already in stock", unit.Serial)
+	}
+	return nil
+}
+
+// commitScanLine books one line of a session and returns the item after
+// the change together with the quantity delta.
+func commitScanLine(tx *gorm.DB, session *models.ScanSession, line models.ScanLine) (*models.Item, models.Quantity, error) {
+	if line.Serial != nil {
+		status, issuedTo := models.SerialInStock, ""
+		if session.Kind == models.ScanWithdraw {
+			if session.Reference == "" {
+				return nil, 0, fmt.Errorf("serial-tracked units need a recipient in the session reference")
+			}
+			status, issuedTo = models.SerialIssued, session.Reference
+		}
+		_, item, delta, err := moveSerial(tx, line.Serial.Serial, status, session.LocationID, issuedTo, session.Comment, session.Reference)
+		return item, delta, err
+	}
+	delta, reason := line.Quantity, models.MovementReceive
+	if session.Kind == models.ScanWithdraw {
+		delta, reason = -line.Quantity, models.MovementWithdraw
+	}
+	var item models.Item
+	if err := adjustQuantity(tx, line.ItemID, delta, nil, &item); err != nil {
+		return nil, 0, err
+	}
+	err := recordMovement(tx, &item, &models.StockMovement{
+		Delta:      delta,
+		Reason:     reason,
+		Comment:    session.Comment,
+		Reference:  session.Reference,
+		LocationID: &session.LocationID,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return &item, delta, nil
+}
@@ -0,0 +1,350 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"goods_wails_app/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrSerialTracked is returned for plain quantity changes of a
+	// serial-tracked item; its stock changes through serial operations only.
+	ErrSerialTracked = errors.New("quantity of a serial-tracked item follows its serial numbers")
+	// ErrNotSerialTracked is returned by serial operations on items without TrackSerials.
+	ErrNotSerialTracked = errors.New("item is not serial-tracked")
+	// ErrDuplicateSerial is returned when a serial number is already registered.
+	ErrDuplicateSerial = errors.New("serial number already registered")
+)
+
+// serialMovementReasons is the ledger reason for a change to each status.
+var serialMovementReasons = map[string]string{
+	models.SerialInStock:    models.MovementReturn,
+	models.SerialIssued:     models.MovementIssue,
+	models.SerialInRepair:   models.MovementRepair,
+	models.SerialWrittenOff: models.MovementWriteOff,
+}
+
+// AddSerials receives one unit of a serial-tracked item per serial number
+// at a location (0 for the default one), each at unitCost (nil for the
+// item's current average cost).
+func (s *DatabaseService) AddSerials(itemID uint, locationID uint, serials []string, comment string, reference string, unitCost *models.Money) (*models.Item, error) {
+	var item models.Item
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		loc, err := resolveLocation(tx, locationID)
+		if err != nil {
+			return err
+		}
+		if err := tx.First(&item, itemID).Error; err != nil {
+			return err
+		}
+		added, err := addSerials(tx, &item, loc, serials, comment, reference, unitCost)
+		if err != nil {
+			return err
+		}
+		if added == 0 {
+			return fmt.Errorf("no serial numbers given")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// EnableSerialTracking turns on serial tracking for an item that already
+// has stock, registering one serial number per unit on hand. The stock must
+// all be at one location (0 for the one holding it). No movements are
+// booked: the quantity stays the same, it just gets serial numbers.
+func (s *DatabaseService) EnableSerialTracking(itemID uint, locationID uint, serials []string) (*models.Item, error) {
+	var item models.Item
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&item, itemID).Error; err != nil {
+			return err
+		}
+		if item.TrackSerials {
+			return fmt.Errorf("%s is already serial-tracked", item.Name)
+		}
+		codes, err := newSerials(tx, serials)
+		if err != nil {
+			return err
+		}
+		if models.Units(int64(len(codes))) != item.Quantity {
+			return fmt.Errorf("%d serial numbers given for %s %s in stock", len(codes), item.Quantity, item.Unit)
+		}
+		loc, err := quantityLocation(tx, item.ID, locationID)
+		if err != nil {
+			return err
+		}
+		var balance models.StockBalance
+		if err := tx.Where("item_id = ? AND location_id = ?", item.ID, loc).Limit(1).Find(&balance).Error; err != nil {
+			return err
+		}
+		if balance.Quantity != item.Quantity {
+			return fmt.Errorf("%w: move all stock of %s to one location first", ErrLocationRequired, item.Name)
+		}
+		item.TrackSerials = true
+		if err := checkTrackingMode(tx, &item, true); err != nil {
+			return err
+		}
+		for _, code := range codes {
+			serial := models.ItemSerial{ItemID: item.ID, Serial: code, Status: models.SerialInStock, LocationID: loc}
+			if err := tx.Create(&serial).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&item).Update("track_serials", true).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// newSerials trims the serial numbers, skipping blank entries, and checks
+// that none is given twice or already registered.
+func newSerials(tx *gorm.DB, serials []string) ([]string, error) {
+	var codes []string
+	seen := map[string]bool{}
+	for _, code := range serials {
+		code = strings.TrimSpace(code)
+		if code == "" {
+			continue
+		}
+		if seen[code] {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateSerial, code)
+		}
+		seen[code] = true
+		var n int64
+		if err := tx.Model(&models.ItemSerial{}).Where("serial = ?", code).Count(&n).Error; err != nil {
+			return nil, err
+		}
+		if n > 0 {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateSerial, code)
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// addSerials registers the serial numbers of item as units in stock at loc,
+// books one receipt per unit and returns how many were added. Blank
+// entries are skipped; item is left holding the resulting quantity.
+func addSerials(tx *gorm.DB, item *models.Item, loc uint, serials []string, comment string, reference string, unitCost *models.Money) (int, error) {
+	if !item.TrackSerials {
+		return 0, ErrNotSerialTracked
+	}
+	codes, err := newSerials(tx, serials)
+	if err != nil {
+		return 0, err
+	}
+	added := 0
+	for _, code := range codes {
+		serial := models.ItemSerial{ItemID: item.ID, Serial: code, Status: models.SerialInStock, LocationID: loc, Comment: comment}
+		if err := tx.Create(&serial).Error; err != nil {
+			return 0, err
+		}
+		if err := adjustQuantity(tx, item.ID, models.Units(1), nil, item); err != nil {
+			return 0, err
+		}
+		err := recordMovement(tx, item, withUnitCost(&models.StockMovement{
+			Delta:      models.Units(1),
+			Reason:     models.MovementReceive,
+			Comment:    comment,
+			Reference:  reference,
+			LocationID: &loc,
+			SerialID:   &serial.ID,
+		}, unitCost))
+		if err != nil {
+			return 0, err
+		}
+		added++
+	}
+	return added, nil
+}
+
+// IssueSerial hands a unit in stock out to a person or department.
+func (s *DatabaseService) IssueSerial(serial string, issuedTo string, comment string) (*models.ItemSerial, error) {
+	if strings.TrimSpace(issuedTo) == "" {
+		return nil, fmt.Errorf("recipient is required")
+	}
+	return s.changeSerialStatus(serial, models.SerialIssued, 0, issuedTo, comment)
+}
+
+// ReturnSerial puts an issued or repaired unit back in stock at a location
+// (0 for the default one).
+func (s *DatabaseService) ReturnSerial(serial string, locationID uint, comment string) (*models.ItemSerial, error) {
+	return s.changeSerialStatus(serial, models.SerialInStock, locationID, "", comment)
+}
+
+// SetSerialStatus moves a unit to any status; written-off units are final.
+// Units returning to stock go back to the location they were last kept at.
+func (s *DatabaseService) SetSerialStatus(serial string, status string, comment string) (*models.ItemSerial, error) {
+	if !models.ValidSerialStatus(status) {
+		return nil, fmt.Errorf("unknown serial status %q", status)
+	}
+	return s.changeSerialStatus(serial, status, 0, "", comment)
+}
+
+// ListItemSerials returns the serial records of an item ordered by serial number.
+func (s *DatabaseService) ListItemSerials(itemID uint) ([]models.ItemSerial, error) {
+	var serials []models.ItemSerial
+	err := s.DB.Preload("Location", unscoped).
+		Where("item_id = ?", itemID).
+		Order("serial asc").
+		Find(&serials).Error
+	return serials, err
+}
+
+// FindSerial returns the unit with exactly this serial number and its item.
+func (s *DatabaseService) FindSerial(serial string) (*models.ItemSerial, error) {
+	var unit models.ItemSerial
+	err := s.DB.Preload("Item", unscoped).Preload("Location", unscoped).
+		Where("serial = ?", strings.TrimSpace(serial)).
+		First(&unit).Error
+	if err != nil {
+		return nil, err
+	}
+	return &unit, nil
+}
+
+// SearchSerials returns units whose serial number contains query,
+// case-insensitive, across all items.
+func (s *DatabaseService) SearchSerials(query string, limit int) ([]models.ItemSerial, error) {
+	var serials []models.ItemSerial
+	pattern := "%" + escapeLike(strings.ToLower(strings.TrimSpace(query))) + "%"
+	q := s.DB.Preload("Item", unscoped).Preload("Location", unscoped).
+		Where(`unicode_lower(serial) LIKE ? ESCAPE '\'`, pattern).
+		Order("serial asc")
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	err := q.Find(&serials).Error
+	return serials, err
+}
+
+// changeSerialStatus moves a unit to status in its own transaction.
+func (s *DatabaseService) changeSerialStatus(code string, status string, locationID uint, issuedTo string, comment string) (*models.ItemSerial, error) {
+	var unit *models.ItemSerial
+	var item *models.Item
+	var delta models.Quantity
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		unit, item, delta, err = moveSerial(tx, code, status, locationID, issuedTo, comment, "")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	unit.Item = item
+	s.notifyLowStock(item, models.IsLowStock(item.Quantity-delta, item.MinStock))
+	return unit, nil
+}
+
+// moveSerial moves a unit to status and books the change. Leaving or
+// entering stock changes the item quantity by one unit, returned as delta;
+// other changes are recorded with a zero delta for the history. The
+// movement reference defaults to the recipient of an issued unit.
+func moveSerial(tx *gorm.DB, code string, status string, locationID uint, issuedTo string, comment string, reference string) (*models.ItemSerial, *models.Item, models.Quantity, error) {
+	var unit models.ItemSerial
+	var item models.Item
+	var delta models.Quantity
+	if err := tx.Where("serial = ?", strings.TrimSpace(code)).First(&unit).Error; err != nil {
+		return nil, nil, 0, err
+	}
+	if unit.Status == models.SerialWrittenOff {
+		return nil, nil, 0, fmt.Errorf("serial %s is written off", unit.Serial)
+	}
+	if unit.Status == status {
+		return nil, nil, 0, fmt.Errorf("serial %s is already %s", unit.Serial, status)
+	}
+	loc := unit.LocationID
+	if status == models.SerialInStock && locationID != 0 {
+		var err error
+		if loc, err = resolveLocation(tx, locationID); err != nil {
+			return nil, nil, 0, err
+		}
+	}
+	if status == models.SerialInStock {
+		delta = models.Units(1)
+	} else if unit.Status == models.SerialInStock {
+		delta = -models.Units(1)
+	}
+	if delta != 0 {
+		if err := adjustQuantity(tx, unit.ItemID, delta, nil, &item); err != nil {
+			return nil, nil, 0, err
+		}
+	} else if err := tx.First(&item, unit.ItemID).Error; err != nil {
+		return nil, nil, 0, err
+	}
+
+	unit.Status = status
+	unit.LocationID = loc
+	unit.IssuedTo = ""
+	if status == models.SerialIssued {
+		unit.IssuedTo = strings.TrimSpace(issuedTo)
+	}
+	if comment != "" {
+		unit.Comment = comment
+	}
+	if err := tx.Save(&unit).Error; err != nil {
+		return nil, nil, 0, err
+	}
+	if reference == "" {
+		reference = unit.IssuedTo
+	}
+	err := recordMovement(tx, &item, &models.StockMovement{
+		Delta:      delta,
+		Reason:     serialMovementReasons[status],
+		Comment:    comment,
+		Reference:  reference,
+		LocationID: &loc,
+		SerialID:   &unit.ID,
+	})
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return &unit, &item, delta, nil
+}
+
+// checkTrackingMode validates the lot/serial flags of item. wasSerial is
+// whether serial tracking was on before the change. Tracking cannot be
+// switched off while units are in stock, which would leave their serials
+// without an item that accounts for them.
+func checkTrackingMode(tx *gorm.DB, item *models.Item, wasSerial bool) error {
+	if !item.TrackSerials {
+		if !wasSerial {
+			return nil
+		}
+		var n int64
+		if err := tx.Model(&models.ItemSerial{}).Where("item_id = ? AND status = ?", item.ID, models.SerialInStock).Count(&n).Error; err != nil {
+			return err
+		}
+		if n > 0 {
+			return fmt.Errorf("%w: %d serial numbers are in stock", ErrSerialTracked, n)
+		}
+		return nil
+	}
+	if item.TrackLots {
+		return fmt.Errorf("an item cannot track both lots and serial numbers")
+	}
+	if item.Precision != 0 {
+		return fmt.Errorf("serial-tracked items are counted in whole units")
+	}
+	if wasSerial {
+		return nil
+	}
+	var n int64
+	if err := tx.Model(&models.ItemSerial{}).Where("item_id = ? AND status = ?", item.ID, models.SerialInStock).Count(&n).Error; err != nil {
+		return err
+	}
+	if item.Quantity != models.Units(n) {
+		return fmt.Errorf("%w: quantity %s does not match %d serial numbers in stock; give serial numbers for the stock on hand when enabling tracking",
+			ErrSerialTracked, item.Quantity, n)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"goods_wails_app/models"
+)
+
+func TestDisableSerialTrackingWithUnitsInStock(t *testing.T) {
+	db := newTestDB(t)
+	yes, no := true, false
+	item, err := db.CreateItem(models.ItemInput{Name: "Дрель", TrackSerials: &yes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.AddSerials(item.ID, 0, []string{"D-1"}, "", "", nil); err != nil {
+		t.Fatal(err)
+	}
+	off := models.ItemInput{Name: "Дрель", Quantity: models.Units(1), TrackSerials: &no}
+	if _, err := db.UpdateItem(item.ID, off); !errors.Is(err, ErrSerialTracked) {
+		t.Fatalf("disabling with a unit in stock: %v, want ErrSerialTracked", err)
+	}
+
+	if _, err := db.IssueSerial("D-1", "Петров", ""); err != nil {
+		t.Fatal(err)
+	}
+	off.Quantity = 0
+	got, err := db.UpdateItem(item.ID, off)
+	if err != nil {
+		t.Fatalf("disabling with no unit in stock: %v", err)
+	}
+	if got.TrackSerials {
+		t.Fatal("serial tracking still on")
+	}
+}
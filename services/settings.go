@@ -0,0 +1,165 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"goods_wails_app/models"
+)
+
+// SettingsFileName is the settings file inside the data directory.
+const SettingsFileName = "settings.json"
+
+// SettingsService keeps the application settings in a JSON file and
+// notifies listeners when they change.
+type SettingsService struct {
+	path      string
+	mu        sync.RWMutex
+	current   models.Settings
+	listeners []func(old, cur models.Settings)
+}
+
+// NewSettingsService loads the settings file from dir. A missing file yields
+// the defaults; a file written by an older version is upgraded and saved back.
+func NewSettingsService(dir string) (*SettingsService, error) {
+	s := &SettingsService{path: filepath.Join(dir, SettingsFileName), current: models.DefaultSettings()}
+	raw, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	loaded, upgraded, err := decodeSettings(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", SettingsFileName, err)
+	}
+	if err := validateSettings(loaded); err != nil {
+		// A hand-edited file must not keep the app from starting.
+		log.Printf("%s: %v; using defaults", SettingsFileName, err)
+		return s, nil
+	}
+	s.current = loaded
+	if upgraded {
+		if err := s.save(loaded); err != nil {
+			log.Printf("save upgraded settings: %v", err)
+		}
+	}
+	return s, nil
+}
+
+// Get returns the current settings.
+func (s *SettingsService) Get() models.Settings {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current
+}
+
+// Update validates and saves next, then calls the change listeners with the
+// previous and new settings. The version field is managed by the service.
+func (s *SettingsService) Update(next models.Settings) (models.Settings, error) {
+	next.Version = models.SettingsVersion
+	next.UpdateRepoOwner = strings.TrimSpace(next.UpdateRepoOwner)
+	next.UpdateRepoName = strings.TrimSpace(next.UpdateRepoName)
+	next.DatabaseFile = strings.TrimSpace(next.DatabaseFile)
+	if err := validateSettings(next); err != nil {
+		return s.Get(), err
+	}
+
+	s.mu.Lock()
+	old := s.current
+	if err := s.save(next); err != nil {
+		s.mu.Unlock()
+		return old, err
+	}
+	s.current = next
+	listeners := append([]func(old, cur models.Settings){}, s.listeners...)
+	s.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn(old, next)
+	}
+	return next, nil
+}
+
+// OnChange registers fn to be called after every successful Update.
+func (s *SettingsService) OnChange(fn func(old, cur models.Settings)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listeners = append(s.listeners, fn)
+}
+
+// save writes the file atomically: a crash leaves either the old or the new
+// settings, never a truncated file.
+func (s *SettingsService) save(settings models.Settings) error {
+	raw, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, raw, 0o644); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// decodeSettings reads a settings file of any known version and reports
+// whether it had to be upgraded. Fields missing from the file keep their
+// defaults, so settings added in later versions need no explicit step.
+func decodeSettings(raw []byte) (models.Settings, bool, error) {
+	settings := models.DefaultSettings()
+	settings.Version = 0
+	if err := json.Unmarshal(raw, &settings); err != nil {
+		return models.Settings{}, false, err
+	}
+	if settings.Version > models.SettingsVersion {
+		// Written by a newer build: keep the fields we understand and leave
+		// the file alone until the user changes something.
+		log.Printf("%s is version %d, this build knows %d", SettingsFileName, settings.Version, models.SettingsVersion)
+		settings.Version = models.SettingsVersion
+		return settings, false, nil
+	}
+	upgraded := settings.Version < models.SettingsVersion
+	// Per-version upgrade steps go here, oldest first:
+	// if settings.Version < 2 { ... }
+	settings.Version = models.SettingsVersion
+	return settings, upgraded, nil
+}
+
+func validateSettings(s models.Settings) error {
+	switch {
+	case s.UpdateRepoOwner == "" || s.UpdateRepoName == "":
+		return fmt.Errorf("update repository owner and name are required")
+	case strings.ContainsAny(s.UpdateRepoOwner+s.UpdateRepoName, "/\\ "):
+		return fmt.Errorf("invalid update repository %s/%s", s.UpdateRepoOwner, s.UpdateRepoName)
+	case s.UpdateCheckIntervalHours < 1:
+		return fmt.Errorf("update check interval must be at least 1 hour")
+	case s.UpdateInitialDelaySeconds < 0:
+		return fmt.Errorf("update initial delay must not be negative")
+	case s.DatabaseFile == "" || s.DatabaseFile != filepath.Base(s.DatabaseFile) || s.DatabaseFile == "." || s.DatabaseFile == "..":
+		return fmt.Errorf("database file must be a plain file name")
+	case s.BackupIntervalHours < 1:
+		return fmt.Errorf("backup interval must be at least 1 hour")
+	case s.BackupKeepDaily < 0 || s.BackupKeepWeekly < 0:
+		return fmt.Errorf("backup retention must not be negative")
+	case s.ExpiryWarningDays < 0:
+		return fmt.Errorf("expiry warning period must not be negative")
+	case s.WindowWidth < models.MinWindowWidth || s.WindowHeight < models.MinWindowHeight:
+		return fmt.Errorf("window must be at least %dx%d", models.MinWindowWidth, models.MinWindowHeight)
+	}
+	return nil
+}
@@ -0,0 +1,300 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"goods_wails_app/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrStocktakeClosed is returned for changes to a posted or cancelled stocktake.
+var ErrStocktakeClosed = errors.New("stocktake is closed")
+
+// stocktakeReference is the reference written to the movements of a stocktake.
+func stocktakeReference(id uint) string {
+	return fmt.Sprintf("INV-%06d", id)
+}
+
+// StartStocktake opens a count of a location (0 for the default one),
+// limited to category when it is not empty, and freezes the expected
+// balance of every item in scope.
+func (s *DatabaseService) StartStocktake(locationID uint, category string, comment string) (*models.Stocktake, error) {
+	var st models.Stocktake
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		loc, err := resolveLocation(tx, locationID)
+		if err != nil {
+			return err
+		}
+		st = models.Stocktake{
+			Status:     models.StocktakeOpen,
+			LocationID: loc,
+			Category:   strings.TrimSpace(category),
+			Comment:    strings.TrimSpace(comment),
+		}
+		st.UserID, st.UserName = s.actor()
+		if err := tx.Create(&st).Error; err != nil {
+			return err
+		}
+		st.Reference = stocktakeReference(st.ID)
+		if err := tx.Model(&st).Update("reference", st.Reference).Error; err != nil {
+			return err
+		}
+		now := time.Now()
+		q := `INSERT INTO stocktake_lines (stocktake_id, item_id, expected, variance, created_at, updated_at)
+			SELECT ?, items.id, COALESCE(stock_balances.quantity, 0), 0, ?, ?
+			FROM items
+			LEFT JOIN stock_balances ON stock_balances.item_id = items.id AND stock_balances.location_id = ?
+			WHERE items.deleted_at IS NULL AND items.track_serials = ?`
+		args := []interface{}{st.ID, now, now, loc, false}
+		if st.Category != "" {
+			q += ` AND items.category = ?`
+			args = append(args, st.Category)
+		}
+		return tx.Exec(q, args...).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetStocktake(st.ID)
+}
+
+// GetStocktake returns a stocktake with its lines ordered by item name.
+func (s *DatabaseService) GetStocktake(id uint) (*models.Stocktake, error) {
+	var st models.Stocktake
+	err := s.DB.Preload("Location", unscoped).
+		Preload("Lines", func(db *gorm.DB) *gorm.DB {
+			return db.Select("stocktake_lines.*").
+				Joins("JOIN items ON items.id = stocktake_lines.item_id").
+				Order("items.name asc, stocktake_lines.id asc")
+		}).
+		Preload("Lines.Item", unscoped).
+		First(&st, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &st, nil
+}
+
+// ListStocktakes returns stocktakes with the given status, or all of them
+// when status is empty, newest first. Lines are not loaded.
+func (s *DatabaseService) ListStocktakes(status string) ([]models.Stocktake, error) {
+	var list []models.Stocktake
+	q := s.DB.Preload("Location", unscoped).Order("id desc")
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+	err := q.Find(&list).Error
+	return list, err
+}
+
+// RecordCount sets the counted quantity of an item in an open stocktake;
+// nil clears it again. An item without a line yet, such as one created
+// after the stocktake was opened, gets one with its current balance as the
+// expected quantity.
+func (s *DatabaseService) RecordCount(stocktakeID uint, itemID uint, counted *models.Quantity) (*models.StocktakeLine, error) {
+	if counted != nil && *counted < 0 {
+		return nil, fmt.Errorf("counted quantity must not be negative")
+	}
+	var line models.StocktakeLine
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		st, err := openStocktake(tx, stocktakeID)
+		if err != nil {
+			return err
+		}
+		var item models.Item
+		if err := tx.First(&item, itemID).Error; err != nil {
+			return err
+		}
+		if item.TrackSerials {
+			return fmt.Errorf("%w: count the serial numbers of %s", ErrSerialTracked, item.Name)
+		}
+		if counted != nil {
+			if err := checkPrecision(&item, *counted); err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("stocktake_id = ? AND item_id = ?", st.ID, itemID).Limit(1).Find(&line).Error; err != nil {
+			return err
+		}
+		if line.ID == 0 {
+			var balance models.StockBalance
+			if err := tx.Where("item_id = ? AND location_id = ?", itemID, st.LocationID).Limit(1).Find(&balance).Error; err != nil {
+				return err
+			}
+			line = models.StocktakeLine{StocktakeID: st.ID, ItemID: itemID, Expected: balance.Quantity}
+		}
+		line.Counted = counted
+		line.Variance = 0
+		if counted != nil {
+			line.Variance = *counted - line.Expected
+		}
+		if err := tx.Save(&line).Error; err != nil {
+			return err
+		}
+		line.Item = &item
+		return tx.Model(st).Update("updated_at", time.Now()).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &line, nil
+}
+
+// PostStocktake writes the variance of every counted line as a stocktake
+// movement, all in one transaction, and closes the stocktake. The
+// variance is added to the current balance, so stock that moved while
+// counting is not undone. Uncounted lines are left alone. A shortfall
+// posts even when the missing stock was reserved; the reservations are
+// then trimmed to the stock that is left.
+func (s *DatabaseService) PostStocktake(id uint) (*models.Stocktake, error) {
+	var adjusted []*models.Item
+	var wasLow []bool
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		st, err := openStocktake(tx, id)
+		if err != nil {
+			return err
+		}
+		var lines []models.StocktakeLine
+		err = tx.Preload("Item", unscoped).
+			Where("stocktake_id = ? AND counted IS NOT NULL AND variance <> 0", id).
+			Order("id asc").
+			Find(&lines).Error
+		if err != nil {
+			return err
+		}
+		for _, line := range lines {
+			var item models.Item
+			if err := adjustCountedQuantity(tx, line.ItemID, line.Variance, &item); err != nil {
+				return fmt.Errorf("%s: %w", line.Item.Name, err)
+			}
+			err := recordMovement(tx, &item, &models.StockMovement{
+				Delta:      line.Variance,
+				Reason:     models.MovementStocktake,
+				Comment:    st.Comment,
+				Reference:  st.Reference,
+				LocationID: &st.LocationID,
+			})
+			if err != nil {
+				return fmt.Errorf("%s: %w", line.Item.Name, err)
+			}
+			if line.Variance < 0 {
+				if err := trimReservations(tx, &item, st.LocationID); err != nil {
+					return err
+				}
+			}
+			adjusted = append(adjusted, &item)
+			wasLow = append(wasLow, models.IsLowStock(item.Quantity-line.Variance, item.MinStock))
+		}
+		return tx.Model(st).Updates(map[string]interface{}{"status": models.StocktakePosted, "closed_at": time.Now()}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, item := range adjusted {
+		s.notifyLowStock(item, wasLow[i])
+	}
+	return s.GetStocktake(id)
+}
+
+// CancelStocktake closes a stocktake without adjusting anything.
+func (s *DatabaseService) CancelStocktake(id uint) (*models.Stocktake, error) {
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		st, err := openStocktake(tx, id)
+		if err != nil {
+			return err
+		}
+		return tx.Model(st).Updates(map[string]interface{}{"status": models.StocktakeCancelled, "closed_at": time.Now()}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetStocktake(id)
+}
+
+// WriteStocktakeReport writes the variance report of a stocktake to path
+// as an A4 PDF: every counted line whose quantity differs, with totals.
+func (s *DatabaseService) WriteStocktakeReport(id uint, path string) error {
+	st, err := s.GetStocktake(id)
+	if err != nil {
+		return err
+	}
+	return writeStocktakePDF(path, st, time.Now())
+}
+
+// openStocktake loads a stocktake and checks that it can still be changed.
+func openStocktake(tx *gorm.DB, id uint) (*models.Stocktake, error) {
+	var st models.Stocktake
+	if err := tx.First(&st, id).Error; err != nil {
+		return nil, err
+	}
+	if st.Status != models.StocktakeOpen {
+		return nil, fmt.Errorf("%w: %s is %s", ErrStocktakeClosed, st.Reference, st.Status)
+	}
+	return &st, nil
+}
+
+// stocktakeStatusTitles names the statuses in the printed report.
+var stocktakeStatusTitles = map[string]string{
+	models.StocktakeOpen:      "открыта",
+	models.StocktakePosted:    "проведена",
+	models.StocktakeCancelled: "отменена",
+}
+
+// writeStocktakePDF renders the variance report of st.
+func writeStocktakePDF(path string, st *models.Stocktake, now time.Time) error {
+	t := pdfTable{
+		title: "Сличительная ведомость " + st.Reference,
+		columns: []pdfColumn{
+			{"№", 10, "R"}, {"Артикул", 28, "L"}, {"Наименование", 70, "L"}, {"Ед.", 12, "L"},
+			{"Учёт", 23, "R"}, {"Факт", 23, "R"}, {"Разница", 24, "R"},
+		},
+		empty: "Расхождений нет",
+	}
+	counted, surplus, shortage := 0, 0, 0
+	for _, line := range st.Lines {
+		if line.Counted == nil {
+			continue
+		}
+		counted++
+		switch {
+		case line.Variance > 0:
+			surplus++
+		case line.Variance < 0:
+			shortage++
+		default:
+			continue
+		}
+		var sku, name, unit string
+		if line.Item != nil {
+			sku, name, unit = line.Item.SKU, line.Item.Name, line.Item.Unit
+		}
+		variance := line.Variance.String()
+		if line.Variance > 0 {
+			variance = "+" + variance
+		}
+		t.rows = append(t.rows, []string{
+			fmt.Sprint(len(t.rows) + 1), sku, name, unit, line.Expected.String(), line.Counted.String(), variance,
+		})
+	}
+
+	location := ""
+	if st.Location != nil {
+		location = st.Location.Name
+	}
+	scope := "Склад: " + location
+	if st.Category != "" {
+		scope += ", категория: " + st.Category
+	}
+	t.header = append(t.header, scope,
+		fmt.Sprintf("Начата %s (%s), статус: %s", st.CreatedAt.Local().Format("02.01.2006 15:04"), st.UserName, stocktakeStatusTitles[st.Status]))
+	if st.Comment != "" {
+		t.header = append(t.header, st.Comment)
+	}
+	t.header = append(t.header, fmt.Sprintf("Посчитано %d из %d позиций; излишки: %d, недостачи: %d. Сформировано %s",
+		counted, len(st.Lines), surplus, shortage, now.Format("02.01.2006 15:04")))
+	return writeTablePDF(path, t)
+}
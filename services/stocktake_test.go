@@ -0,0 +1,96 @@
+package services
+
+import (
+	"testing"
+
+	"goods_wails_app/models"
+)
+
+func TestPostStocktakeShortfallOfReservedStock(t *testing.T) {
+	u := models.Units
+	tests := []struct {
+		name    string
+		reserve func(t *testing.T, db *DatabaseService, itemID uint)
+		counted models.Quantity
+	}{
+		{
+			name: "manual reservation",
+			reserve: func(t *testing.T, db *DatabaseService, itemID uint) {
+				in := models.ReservationInput{ItemID: itemID, Quantity: u(8), Holder: "Цех 1"}
+				if _, err := db.CreateReservation(in); err != nil {
+					t.Fatal(err)
+				}
+			},
+			counted: u(5),
+		},
+		{
+			name: "approved request at the location",
+			reserve: func(t *testing.T, db *DatabaseService, itemID uint) {
+				req, err := db.CreateIssueRequest(models.IssueRequestInput{
+					Requester: "Иванов",
+					Lines:     []models.IssueRequestLineInput{{ItemID: itemID, Quantity: u(6)}},
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, err := db.ApproveIssueRequest(req.ID, ""); err != nil {
+					t.Fatal(err)
+				}
+			},
+			counted: u(2),
+		},
+		{
+			name: "everything missing",
+			reserve: func(t *testing.T, db *DatabaseService, itemID uint) {
+				in := models.ReservationInput{ItemID: itemID, Quantity: u(10), Holder: "Цех 2"}
+				if _, err := db.CreateReservation(in); err != nil {
+					t.Fatal(err)
+				}
+			},
+			counted: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			item, err := db.CreateItem(models.ItemInput{Name: "Кабель ВВГ", Quantity: u(10)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.reserve(t, db, item.ID)
+
+			st, err := db.StartStocktake(0, "", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			counted := tt.counted
+			if _, err := db.RecordCount(st.ID, item.ID, &counted); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := db.PostStocktake(st.ID); err != nil {
+				t.Fatalf("post: %v", err)
+			}
+
+			checkLedger(t, db, item.ID, tt.counted)
+			var got models.Item
+			if err := db.DB.First(&got, item.ID).Error; err != nil {
+				t.Fatal(err)
+			}
+			if got.Reserved > got.Quantity {
+				t.Fatalf("reserved %s exceeds quantity %s", got.Reserved, got.Quantity)
+			}
+			var held models.Quantity
+			err = db.DB.Model(&models.Reservation{}).Where("item_id = ?", item.ID).
+				Select("COALESCE(SUM(quantity), 0)").Scan(&held).Error
+			if err != nil {
+				t.Fatal(err)
+			}
+			if held != got.Reserved {
+				t.Fatalf("reservations hold %s, item reserved %s", held, got.Reserved)
+			}
+			if available, err := availableAt(db.DB, item.ID, st.LocationID); err != nil || available < 0 {
+				t.Fatalf("available at location %s, %v", available, err)
+			}
+		})
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -24,16 +25,29 @@ var updateSwapBat []byte
 
 // UpdaterService handles checking and downloading application updates from GitHub Releases.
 type UpdaterService struct {
-	httpClient  *http.Client
+	httpClient *http.Client
+	exePath    string
+	// mu guards the repository, which SetRepo changes while checks run.
+	mu          sync.Mutex
 	repoOwner   string
 	repoName    string
 	assetFilter *regexp.Regexp
-	exePath     string
 }
 
 // NewUpdaterService constructs a new updater for the given executable path.
 // repoOwner/repoName specify the GitHub repository to check.
 func NewUpdaterService(exePath string, repoOwner string, repoName string) *UpdaterService {
+	u := &UpdaterService{
+		httpClient: &http.Client{Timeout: 20 * time.Second},
+		exePath:    exePath,
+	}
+	u.SetRepo(repoOwner, repoName)
+	return u
+}
+
+// SetRepo switches the GitHub repository checked for releases. It is safe
+// to call while a check or download is running.
+func (u *UpdaterService) SetRepo(repoOwner string, repoName string) {
 	// Attempt to pick a reasonable asset filter for Windows .exe
 	// The filter will match assets containing repo name and .exe
 	// Relax filter to any .exe asset (we'll rely on GitHub tag for versioning)
@@ -42,13 +56,11 @@ func NewUpdaterService(exePath string, repoOwner string, repoName string) *Updat
 		// Fallback to any asset for non-Windows, though this project targets Windows per workspace
 		pattern = fmt.Sprintf(`(?i)%s`, regexp.QuoteMeta(repoName))
 	}
-	return &UpdaterService{
-		httpClient:  &http.Client{Timeout: 20 * time.Second},
-		repoOwner:   repoOwner,
-		repoName:    repoName,
-		assetFilter: regexp.MustCompile(pattern),
-		exePath:     exePath,
-	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.repoOwner = repoOwner
+	u.repoName = repoName
+	u.assetFilter = regexp.MustCompile(pattern)
 }
 
 type githubRelease struct {
@@ -63,7 +75,10 @@ type githubAsset struct {
 
 // CheckLatest queries GitHub for the latest release and returns tag and asset URL if any.
 func (u *UpdaterService) CheckLatest(ctx context.Context) (tag string, assetURL string, err error) {
+	u.mu.Lock()
 	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases/latest", u.repoOwner, u.repoName)
+	assetFilter := u.assetFilter
+	u.mu.Unlock()
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
 	if err != nil {
 		return "", "", err
@@ -91,7 +106,7 @@ func (u *UpdaterService) CheckLatest(ctx context.Context) (tag string, assetURL
 
 	// pick best asset
 	for _, a := range rel.Assets {
-		if u.assetFilter.MatchString(a.Name) {
+		if assetFilter.MatchString(a.Name) {
 			return rel.TagName, a.BrowserDownloadURL, nil
 		}
 	}
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"goods_wails_app/models"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidCredentials is returned by Authenticate for an unknown user,
+	// a wrong password or a disabled account alike.
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	// ErrNotAuthenticated is returned for actions attempted before logging in.
+	ErrNotAuthenticated = errors.New("not logged in")
+	// ErrForbidden is returned when the user's role does not allow an action.
+	ErrForbidden = errors.New("permission denied")
+	// ErrDuplicateUsername is returned when a username is already taken.
+	ErrDuplicateUsername = errors.New("username is already taken")
+	// ErrLastAdmin prevents disabling or demoting the only active admin.
+	ErrLastAdmin = errors.New("at least one active admin is required")
+	// ErrSetupDone is returned by SetupAdmin once any user exists.
+	ErrSetupDone = errors.New("users are already set up")
+)
+
+// MinPasswordLength is the shortest password accepted for an account.
+const MinPasswordLength = 6
+
+// dummyHash is compared against when the user does not exist, so a login
+// takes the same time whether or not the username is known.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("not a password"), bcrypt.DefaultCost)
+
+// HasUsers reports whether any account exists. Until one does, the
+// application asks for the first admin to be created.
+func (s *DatabaseService) HasUsers() (bool, error) {
+	var n int64
+	err := s.DB.Model(&models.User{}).Count(&n).Error
+	return n > 0, err
+}
+
+// SetupAdmin creates the first admin account. It fails once any user exists.
+func (s *DatabaseService) SetupAdmin(username string, password string) (*models.User, error) {
+	var user *models.User
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var n int64
+		if err := tx.Model(&models.User{}).Count(&n).Error; err != nil {
+			return err
+		}
+		if n > 0 {
+			return ErrSetupDone
+		}
+		var err error
+		user, err = createUser(tx, models.UserInput{Username: username, Role: models.RoleAdmin, Password: password})
+		return err
+	})
+	return user, err
+}
+
+// Authenticate checks a username and password and records the login time.
+func (s *DatabaseService) Authenticate(username string, password string) (*models.User, error) {
+	var user models.User
+	err := s.DB.Where("username = ?", normalizeUsername(username)).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil || user.Disabled {
+		return nil, ErrInvalidCredentials
+	}
+	now := time.Now()
+	user.LastLoginAt = &now
+	if err := s.DB.Model(&user).UpdateColumn("last_login_at", now).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetUser returns the account with the given id.
+func (s *DatabaseService) GetUser(id uint) (*models.User, error) {
+	var user models.User
+	if err := s.DB.First(&user, id).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// ListUsers returns all accounts, disabled ones included, ordered by username.
+func (s *DatabaseService) ListUsers() ([]models.User, error) {
+	var users []models.User
+	err := s.DB.Order("username asc").Find(&users).Error
+	return users, err
+}
+
+// CreateUser adds an account.
+func (s *DatabaseService) CreateUser(in models.UserInput) (*models.User, error) {
+	var user *models.User
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		user, err = createUser(tx, in)
+		return err
+	})
+	return user, err
+}
+
+// UpdateUser changes an account. An empty password keeps the current one.
+// The last active admin cannot be disabled or given another role.
+func (s *DatabaseService) UpdateUser(id uint, in models.UserInput) (*models.User, error) {
+	var user models.User
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&user, id).Error; err != nil {
+			return err
+		}
+		if err := applyUserInput(tx, &user, in); err != nil {
+			return err
+		}
+		if err := tx.Save(&user).Error; err != nil {
+			return err
+		}
+		var admins int64
+		if err := tx.Model(&models.User{}).Where("role = ? AND disabled = ?", models.RoleAdmin, false).Count(&admins).Error; err != nil {
+			return err
+		}
+		if admins == 0 {
+			return ErrLastAdmin
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// ChangePassword replaces a user's password after checking the current one.
+func (s *DatabaseService) ChangePassword(id uint, current string, next string) error {
+	var user models.User
+	if err := s.DB.First(&user, id).Error; err != nil {
+		return err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(current)) != nil {
+		return ErrInvalidCredentials
+	}
+	hash, err := hashPassword(next)
+	if err != nil {
+		return err
+	}
+	return s.DB.Model(&user).Update("password_hash", hash).Error
+}
+
+func createUser(tx *gorm.DB, in models.UserInput) (*models.User, error) {
+	if in.Password == "" {
+		return nil, fmt.Errorf("password is required")
+	}
+	user := &models.User{}
+	if err := applyUserInput(tx, user, in); err != nil {
+		return nil, err
+	}
+	if err := tx.Create(user).Error; err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// applyUserInput validates in and copies it onto user.
+func applyUserInput(tx *gorm.DB, user *models.User, in models.UserInput) error {
+	username := normalizeUsername(in.Username)
+	if username == "" {
+		return fmt.Errorf("username is required")
+	}
+	if !models.ValidRole(in.Role) {
+		return fmt.Errorf("unknown role %q", in.Role)
+	}
+	var n int64
+	if err := tx.Model(&models.User{}).Where("username = ? AND id <> ?", username, user.ID).Count(&n).Error; err != nil {
+		return err
+	}
+	if n > 0 {
+		return fmt.Errorf("%w: %s", ErrDuplicateUsername, username)
+	}
+	if in.Password != "" {
+		hash, err := hashPassword(in.Password)
+		if err != nil {
+			return err
+		}
+		user.PasswordHash = hash
+	}
+	user.Username = username
+	user.DisplayName = strings.TrimSpace(in.DisplayName)
+	user.Role = in.Role
+	user.Disabled = in.Disabled
+	return nil
+}
+
+func hashPassword(password string) (string, error) {
+	if utf8.RuneCountInString(password) < MinPasswordLength {
+		return "", fmt.Errorf("password must be at least %d characters", MinPasswordLength)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func normalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"goods_wails_app/models"
+
+	"gorm.io/gorm"
+)
+
+// valueMovement prices a stock movement of item before it is stored and
+// keeps the item's cost layers in step. A receipt adds a layer at
+// m.UnitCost, or at the current average cost unless m.CostGiven; a
+// withdrawal takes its cost out of the layers by the item's cost method.
+// Transfers only move stock between locations and keep their value.
+func valueMovement(tx *gorm.DB, item *models.Item, m *models.StockMovement) error {
+	if m.Delta == 0 || m.Reason == models.MovementTransfer {
+		return nil
+	}
+	if m.UnitCost < 0 {
+		return fmt.Errorf("unit cost must not be negative")
+	}
+	if m.Delta > 0 {
+		if !m.CostGiven {
+			cost, err := currentUnitCost(tx, item.ID)
+			if err != nil {
+				return err
+			}
+			m.UnitCost = cost
+		}
+		m.Cost = m.UnitCost.Amount(m.Delta)
+		return tx.Create(&models.CostLayer{ItemID: item.ID, Quantity: m.Delta, Value: m.Cost}).Error
+	}
+	cost, err := consumeLayers(tx, item, -m.Delta)
+	if err != nil {
+		return err
+	}
+	m.Cost = -cost
+	m.UnitCost = cost.PerUnit(-m.Delta)
+	return nil
+}
+
+// withUnitCost gives receipt m the unit cost a caller passed, if any; nil
+// leaves it at the item's current average cost.
+func withUnitCost(m *models.StockMovement, unitCost *models.Money) *models.StockMovement {
+	if unitCost != nil {
+		m.UnitCost, m.CostGiven = *unitCost, true
+	}
+	return m
+}
+
+// currentUnitCost is the average cost of the item's stock on hand or, when
+// there is none, the unit cost of its latest receipt.
+func currentUnitCost(tx *gorm.DB, itemID uint) (models.Money, error) {
+	var total struct {
+		Quantity models.Quantity
+		Value    models.Money
+	}
+	err := tx.Model(&models.CostLayer{}).Where("item_id = ?", itemID).
+		Select("COALESCE(SUM(quantity), 0) AS quantity, COALESCE(SUM(value), 0) AS value").
+		Scan(&total).Error
+	if err != nil {
+		return 0, err
+	}
+	if total.Quantity > 0 {
+		return total.Value.PerUnit(total.Quantity), nil
+	}
+	var last models.StockMovement
+	err = tx.Select("unit_cost").Where("item_id = ? AND delta > 0 AND reason <> ?", itemID, models.MovementTransfer).
+		Order("id desc").Limit(1).Find(&last).Error
+	return last.UnitCost, err
+}
+
+// consumeLayers takes qty out of the item's cost layers and returns its
+// cost. FIFO uses the oldest layers first; average costing takes the same
+// share of every layer and merges what is left into one. Stock without
+// layers, such as what was on hand before costing existed, costs nothing.
+func consumeLayers(tx *gorm.DB, item *models.Item, qty models.Quantity) (models.Money, error) {
+	var layers []models.CostLayer
+	if err := tx.Where("item_id = ?", item.ID).Order("id asc").Find(&layers).Error; err != nil {
+		return 0, err
+	}
+	if item.CostMethod == models.CostFIFO {
+		var cost models.Money
+		for i := range layers {
+			if qty <= 0 {
+				break
+			}
+			l := &layers[i]
+			take := min(qty, l.Quantity)
+			value := l.Value.Share(take, l.Quantity)
+			qty -= take
+			cost += value
+			if take == l.Quantity {
+				if err := tx.Delete(l).Error; err != nil {
+					return 0, err
+				}
+				continue
+			}
+			err := tx.Model(l).Updates(map[string]interface{}{"quantity": l.Quantity - take, "value": l.Value - value}).Error
+			if err != nil {
+				return 0, err
+			}
+		}
+		return cost, nil
+	}
+	var onHand models.Quantity
+	var value models.Money
+	for _, l := range layers {
+		onHand += l.Quantity
+		value += l.Value
+	}
+	take := min(qty, onHand)
+	cost := value.Share(take, onHand)
+	if err := tx.Where("item_id = ?", item.ID).Delete(&models.CostLayer{}).Error; err != nil {
+		return 0, err
+	}
+	if onHand > take {
+		merged := models.CostLayer{ItemID: item.ID, Quantity: onHand - take, Value: value - cost}
+		if err := tx.Create(&merged).Error; err != nil {
+			return 0, err
+		}
+	}
+	return cost, nil
+}
+
+// GetValuationReport returns the value of the stock on hand at asOf (zero
+// for now), per item and per category. Quantities and values are summed
+// from the ledger up to asOf, so past dates use the costs booked back then;
+// items deleted by then are left out.
+func (s *DatabaseService) GetValuationReport(asOf time.Time) (*models.ValuationReport, error) {
+	if asOf.IsZero() {
+		asOf = time.Now()
+	}
+	asOf = asOf.Local() // stored times are local
+	var sums []struct {
+		ItemID   uint
+		Quantity models.Quantity
+		Value    models.Money
+	}
+	err := s.DB.Model(&models.StockMovement{}).
+		Select("item_id, SUM(delta) AS quantity, SUM(cost) AS value").
+		Where("created_at <= ?", asOf).
+		Group("item_id").
+		Having("SUM(delta) <> 0 OR SUM(cost) <> 0").
+		Scan(&sums).Error
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint, len(sums))
+	for i, sum := range sums {
+		ids[i] = sum.ItemID
+	}
+	var items []models.Item
+	if len(ids) > 0 {
+		err := s.DB.Unscoped().Where("id IN ?", ids).
+			Where("deleted_at IS NULL OR deleted_at > ?", asOf).
+			Find(&items).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+	byID := make(map[uint]*models.Item, len(items))
+	for i := range items {
+		byID[items[i].ID] = &items[i]
+	}
+
+	report := &models.ValuationReport{AsOf: asOf, Lines: []models.ValuationLine{}, Categories: []models.ValuationCategory{}}
+	categories := map[string]*models.ValuationCategory{}
+	for _, sum := range sums {
+		item, ok := byID[sum.ItemID]
+		if !ok {
+			continue
+		}
+		report.Lines = append(report.Lines, models.ValuationLine{
+			ItemID:   item.ID,
+			Name:     item.Name,
+			SKU:      item.SKU,
+			Category: item.Category,
+			Unit:     item.Unit,
+			Quantity: sum.Quantity,
+			Value:    sum.Value,
+			UnitCost: sum.Value.PerUnit(sum.Quantity),
+		})
+		cat := categories[item.Category]
+		if cat == nil {
+			cat = &models.ValuationCategory{Category: item.Category}
+			categories[item.Category] = cat
+		}
+		cat.Items++
+		cat.Value += sum.Value
+		report.Total += sum.Value
+	}
+	sort.Slice(report.Lines, func(i, j int) bool {
+		a, b := report.Lines[i], report.Lines[j]
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		return a.Name < b.Name
+	})
+	for _, cat := range categories {
+		report.Categories = append(report.Categories, *cat)
+	}
+	sort.Slice(report.Categories, func(i, j int) bool {
+		return report.Categories[i].Category < report.Categories[j].Category
+	})
+	return report, nil
+}
@@ -0,0 +1,196 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"goods_wails_app/models"
+)
+
+// receipt is a receipt in a costing test; a nil cost leaves it at the
+// average cost.
+type receipt struct {
+	qty  models.Quantity
+	cost *models.Money
+}
+
+func rub(n int64) *models.Money {
+	m := models.Money(n * 100)
+	return &m
+}
+
+func TestWithdrawalCost(t *testing.T) {
+	u := models.Units
+	tests := []struct {
+		name   string
+		method string
+		// unlayered is stock without cost layers, as on hand before
+		// costing existed.
+		unlayered models.Quantity
+		receipts  []receipt
+		withdraw  models.Quantity
+		wantCost  models.Money // of the withdrawal
+		wantValue models.Money // of the stock left
+	}{
+		{
+			name:      "fifo takes the oldest layers first",
+			method:    models.CostFIFO,
+			receipts:  []receipt{{u(10), rub(100)}, {u(10), rub(200)}},
+			withdraw:  u(15),
+			wantCost:  *rub(2000),
+			wantValue: *rub(1000),
+		},
+		{
+			name:      "average takes the mean cost",
+			method:    models.CostAverage,
+			receipts:  []receipt{{u(10), rub(100)}, {u(10), rub(200)}},
+			withdraw:  u(15),
+			wantCost:  *rub(2250),
+			wantValue: *rub(750),
+		},
+		{
+			name:      "fifo layers running out",
+			method:    models.CostFIFO,
+			unlayered: u(5),
+			receipts:  []receipt{{u(5), rub(100)}},
+			withdraw:  u(8),
+			wantCost:  *rub(500),
+			wantValue: 0,
+		},
+		{
+			name:      "average layers running out",
+			method:    models.CostAverage,
+			unlayered: u(5),
+			receipts:  []receipt{{u(5), rub(100)}},
+			withdraw:  u(8),
+			wantCost:  *rub(500),
+			wantValue: 0,
+		},
+		{
+			name:      "stock without layers is worth nothing",
+			method:    models.CostFIFO,
+			unlayered: u(10),
+			withdraw:  u(4),
+			wantCost:  0,
+			wantValue: 0,
+		},
+		{
+			name:      "receipt without a cost takes the average",
+			method:    models.CostAverage,
+			receipts:  []receipt{{u(10), rub(100)}, {u(10), nil}},
+			withdraw:  u(10),
+			wantCost:  *rub(1000),
+			wantValue: *rub(1000),
+		},
+		{
+			name:      "free receipt",
+			method:    models.CostAverage,
+			receipts:  []receipt{{u(10), rub(100)}, {u(10), rub(0)}},
+			withdraw:  u(10),
+			wantCost:  *rub(500),
+			wantValue: *rub(500),
+		},
+		{
+			name:      "free receipt with fifo",
+			method:    models.CostFIFO,
+			receipts:  []receipt{{u(10), rub(100)}, {u(10), rub(0)}},
+			withdraw:  u(15),
+			wantCost:  *rub(1000),
+			wantValue: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			method := tt.method
+			item, err := db.CreateItem(models.ItemInput{Name: "Краска", Quantity: tt.unlayered, CostMethod: &method})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := db.DB.Where("item_id = ?", item.ID).Delete(&models.CostLayer{}).Error; err != nil {
+				t.Fatal(err)
+			}
+			for _, r := range tt.receipts {
+				if _, err := db.ReceiveQuantity(item.ID, 0, r.qty, "", "", r.cost); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if _, err := db.WithdrawQuantity(item.ID, 0, tt.withdraw, "", 0); err != nil {
+				t.Fatal(err)
+			}
+
+			var out models.StockMovement
+			if err := db.DB.Where("item_id = ? AND reason = ?", item.ID, models.MovementWithdraw).First(&out).Error; err != nil {
+				t.Fatal(err)
+			}
+			if out.Cost != -tt.wantCost {
+				t.Errorf("withdrawal cost %s, want %s", -out.Cost, tt.wantCost)
+			}
+			var layers models.Money
+			if err := db.DB.Model(&models.CostLayer{}).Where("item_id = ?", item.ID).
+				Select("COALESCE(SUM(value), 0)").Scan(&layers).Error; err != nil {
+				t.Fatal(err)
+			}
+			report, err := db.GetValuationReport(time.Time{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if layers != tt.wantValue || report.Total != tt.wantValue {
+				t.Errorf("stock left worth %s in layers, %s in the report; want %s", layers, report.Total, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestValuationReportAsOf(t *testing.T) {
+	db := newTestDB(t)
+	fifo := models.CostFIFO
+	item, err := db.CreateItem(models.ItemInput{Name: "Краска", CostMethod: &fifo})
+	if err != nil {
+		t.Fatal(err)
+	}
+	steps := []func() error{
+		func() error { _, err := db.ReceiveQuantity(item.ID, 0, models.Units(10), "", "", rub(100)); return err },
+		func() error { _, err := db.ReceiveQuantity(item.ID, 0, models.Units(10), "", "", rub(200)); return err },
+		func() error { _, err := db.WithdrawQuantity(item.ID, 0, models.Units(15), "", 0); return err },
+	}
+	// The ledger entries are dated one day apart, starting on day 1.
+	day := func(n int) time.Time { return time.Date(2025, 3, n, 12, 0, 0, 0, time.Local) }
+	for i, step := range steps {
+		if err := step(); err != nil {
+			t.Fatal(err)
+		}
+		err := db.DB.Model(&models.StockMovement{}).Where("item_id = ? AND delta <> 0", item.ID).
+			Where("id = (SELECT MAX(id) FROM stock_movements)").
+			Update("created_at", day(i+1)).Error
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		asOf      time.Time
+		wantQty   models.Quantity
+		wantValue models.Money
+	}{
+		{day(1).Add(-time.Hour), 0, 0},
+		{day(1), models.Units(10), *rub(1000)},
+		{day(2).Add(-time.Hour), models.Units(10), *rub(1000)},
+		{day(2).Add(time.Hour), models.Units(20), *rub(3000)},
+		{day(3), models.Units(5), *rub(1000)},
+		{time.Time{}, models.Units(5), *rub(1000)},
+	}
+	for _, tt := range tests {
+		report, err := db.GetValuationReport(tt.asOf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var qty models.Quantity
+		for _, line := range report.Lines {
+			qty += line.Quantity
+		}
+		if qty != tt.wantQty || report.Total != tt.wantValue {
+			t.Errorf("as of %s: %s worth %s, want %s worth %s", tt.asOf, qty, report.Total, tt.wantQty, tt.wantValue)
+		}
+	}
+}